@@ -0,0 +1,297 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// AppSetFilePath is the app set file used by 'apply'
+var AppSetFilePath string
+
+// ApplyUpdate is used to update all apps of the app set when a new version is available
+var ApplyUpdate bool
+
+// ApplyPrune is used to uninstall the apps that are not in the app set
+var ApplyPrune bool
+
+// appSetPlan is what needs to be done to make the cluster match the app set
+type appSetPlan struct {
+	installs   []utils.AppToInstall
+	updates    []operator.App
+	uninstalls []string
+	upToDate   []string
+	notes      []string
+}
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:     "apply",
+	Example: "kubemart apply -f kubemart.yaml\nkubemart apply -f kubemart.yaml --update --prune --yes",
+	Short:   "Make the cluster run the applications listed in a file",
+	Long: `This command will read the apps (and their plans) the cluster should have from a file e.g.
+
+apps:
+- name: wordpress
+  plan: 10GB
+- name: rabbitmq
+  update: true
+
+Then it installs the missing apps (and their dependencies), updates the apps that have
+'update: true' (or all of them with '--update') when a new version is available and, with
+'--prune', uninstalls the apps that are not in the file. The plan is printed first.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := validateDryRunAndOutputFlags()
+		if err != nil {
+			return err
+		}
+
+		appSet, err := utils.ReadAppSetFile(AppSetFilePath)
+		if err != nil {
+			return err
+		}
+
+		cs, err := NewClientFromLocalKubeConfig()
+		if err != nil {
+			return err
+		}
+		cs.DryRun = DryRun
+
+		plan, err := cs.PlanAppSet(appSet, ApplyUpdate, ApplyPrune)
+		if err != nil {
+			return err
+		}
+
+		plan.print(messages())
+		if plan.isEmpty() {
+			fmt.Println("Nothing to do, the cluster matches the file")
+			return nil
+		}
+
+		_, err = cs.validateOnlyLocalApps(plan.installs)
+		if err != nil {
+			return err
+		}
+
+		if DryRun == DryRunClient {
+			fmt.Println("Nothing was changed (dry run: client)")
+			return nil
+		}
+
+		if len(plan.uninstalls) > 0 && !proceedWithoutPrompt {
+			proceed, err := confirm(promptInput, os.Stdout, fmt.Sprintf("Are you sure want to uninstall %s app(s)?", strings.Join(plan.uninstalls, ", ")))
+			if err != nil {
+				return fmt.Errorf("%v - use '--yes' to apply without confirmation", err)
+			}
+
+			if !proceed {
+				return fmt.Errorf("operation cancelled")
+			}
+		}
+
+		return cs.RunApply(plan)
+	},
+}
+
+// PlanAppSet compares the app set with the apps installed in the cluster
+func (cs *Clientset) PlanAppSet(appSet *utils.KubemartAppSet, updateAll, prune bool) (*appSetPlan, error) {
+	plan := &appSetPlan{}
+
+	installedApps, err := cs.ListApps()
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make(map[string]operator.App)
+	for _, app := range installedApps.Items {
+		installed[app.Name] = app
+	}
+
+	requested := []utils.AppToInstall{}
+	for _, entry := range appSet.Apps {
+		_, name := utils.ParseAppRef(entry.Name)
+		app, found := installed[name]
+		if !found {
+			planLabel, err := entry.PlanLabelOrSmallest()
+			if err != nil {
+				return nil, err
+			}
+
+			requested = append(requested, utils.AppToInstall{Name: entry.Name, PlanLabel: planLabel})
+			continue
+		}
+
+		if !app.DeletionTimestamp.IsZero() {
+			plan.notes = append(plan.notes, fmt.Sprintf("%s app is being deleted - apply again later to install it", name))
+			continue
+		}
+
+		if entry.Plan != "" {
+			planValue, err := utils.GetAppPlanValueByLabel(entry.Name, entry.Plan)
+			if err == nil && planValue != app.Spec.Plan {
+				plan.notes = append(plan.notes, fmt.Sprintf("%s app does not run %s plan - plans of installed apps are not changed", name, entry.Plan))
+			}
+		}
+
+		if app.Status.NewUpdateAvailable && (entry.Update || updateAll) {
+			plan.updates = append(plan.updates, app)
+		} else {
+			plan.upToDate = append(plan.upToDate, name)
+		}
+	}
+
+	plan.installs, _, err = utils.ResolveDependencies(requested, func(appName string) bool {
+		_, found := installed[appName]
+		return found
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if prune {
+		// dependencies of the listed apps are kept, even if they are not listed
+		keep, err := neededApps(appSet)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, app := range installedApps.Items {
+			if !keep[app.Name] && app.DeletionTimestamp.IsZero() {
+				plan.uninstalls = append(plan.uninstalls, app.Name)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// neededApps returns the names of the apps of the app set and their dependencies
+func neededApps(appSet *utils.KubemartAppSet) (map[string]bool, error) {
+	all := []utils.AppToInstall{}
+	for _, entry := range appSet.Apps {
+		all = append(all, utils.AppToInstall{Name: entry.Name, PlanLabel: entry.Plan})
+	}
+
+	apps, _, err := utils.ResolveDependencies(all, func(appName string) bool { return false })
+	if err != nil {
+		return nil, err
+	}
+
+	needed := make(map[string]bool)
+	for _, app := range apps {
+		_, name := utils.ParseAppRef(app.Name)
+		needed[name] = true
+	}
+
+	return needed, nil
+}
+
+// RunApply executes the plan: installs, then updates, then uninstalls
+func (cs *Clientset) RunApply(plan *appSetPlan) error {
+	if len(plan.installs) > 0 {
+		err := cs.RunInstall(plan.installs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(plan.updates) > 0 {
+		updatedApps := []string{}
+		checks := make(map[string]appCheck)
+		for _, app := range plan.updates {
+			_, err := cs.updateApp(app.Name)
+			if err != nil {
+				return fmt.Errorf("unable to update %s app - %v", app.Name, err)
+			}
+
+			updatedApps = append(updatedApps, app.Name)
+			checks[app.Name] = updateCheck(app.Status.NewUpdateVersion, app.Status.LastStatus)
+		}
+		fmt.Printf("App(s) now scheduled to be updated%s: %s\n", dryRunSuffix(), strings.Join(updatedApps, ", "))
+
+		if Wait && dryRunSuffix() == "" {
+			err := cs.WaitForApps(updatedApps, WaitTimeout, func(eventType watch.EventType, app *operator.App) (bool, error) {
+				return checks[app.Name](eventType, app)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(plan.uninstalls) > 0 {
+		err := cs.RunUninstall([]string{strings.Join(plan.uninstalls, ",")})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *appSetPlan) isEmpty() bool {
+	return len(p.installs) == 0 && len(p.updates) == 0 && len(p.uninstalls) == 0
+}
+
+// print writes the plan in a human-friendly way
+func (p *appSetPlan) print(w io.Writer) {
+	fmt.Fprintln(w, "Plan:")
+	for _, app := range p.installs {
+		line := fmt.Sprintf("  + install %s", app.Name)
+		if app.PlanLabel != "" {
+			line += fmt.Sprintf(" (plan: %s)", app.PlanLabel)
+		}
+		if len(app.RequiredBy) > 0 {
+			line += fmt.Sprintf(" - required by %s", strings.Join(app.RequiredBy, ", "))
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	for _, app := range p.updates {
+		fmt.Fprintf(w, "  ~ update %s (%s -> %s)\n", app.Name, app.Status.InstalledVersion, app.Status.NewUpdateVersion)
+	}
+
+	for _, appName := range p.uninstalls {
+		fmt.Fprintf(w, "  - uninstall %s\n", appName)
+	}
+
+	for _, appName := range p.upToDate {
+		fmt.Fprintf(w, "  = %s (no changes)\n", appName)
+	}
+
+	for _, note := range p.notes {
+		fmt.Fprintf(w, "Note: %s\n", note)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringVarP(&AppSetFilePath, "filename", "f", utils.DefaultAppSetFileName, "file listing the apps the cluster should have")
+	applyCmd.Flags().BoolVar(&ApplyUpdate, "update", false, "update all apps of the file when a new version is available")
+	applyCmd.Flags().BoolVar(&ApplyPrune, "prune", false, "uninstall the apps that are not in the file (dependencies of the apps in the file are kept)")
+	applyCmd.Flags().BoolVarP(&proceedWithoutPrompt, "yes", "y", false, "skip interactive y/n prompt by answering 'y'")
+	addDryRunFlag(applyCmd)
+	addWaitFlags(applyCmd)
+}
@@ -0,0 +1,642 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubemart/kubemart-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// CatalogRef is the Git branch or tag used by 'catalog add'
+var CatalogRef string
+
+// CatalogName is the catalog used by 'catalog pin' and 'catalog unpin'
+var CatalogName string
+
+// CatalogChangesName is the only catalog shown by 'catalog changes', all of them when empty
+var CatalogChangesName string
+
+// CatalogChangesSince is the commit or duration used by 'catalog changes'
+var CatalogChangesSince string
+
+// CatalogLintOutput is the output format of 'catalog lint' i.e. text or json
+var CatalogLintOutput string
+
+// catalogLintResult is the output of 'catalog lint' for one catalog, in json format
+type catalogLintResult struct {
+	Path   string            `json:"path"`
+	Issues []utils.LintIssue `json:"issues"`
+}
+
+// LockFilePath is the path of kubemart.lock file
+var LockFilePath string
+
+// catalogCmd represents the catalog command
+var catalogCmd = &cobra.Command{
+	Use:     "catalog",
+	Example: "kubemart catalog list",
+	Short:   "Manage the catalogs where applications are fetched from",
+}
+
+// catalogAddCmd represents the catalog add command
+var catalogAddCmd = &cobra.Command{
+	Use:     "add NAME GIT_URL",
+	Example: "kubemart catalog add internal https://github.com/acme/kubernetes-marketplace.git --ref main",
+	Short:   "Register a catalog and fetch its applications",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source := utils.CatalogSource{
+			Name: args[0],
+			URL:  args[1],
+			Ref:  CatalogRef,
+		}
+		utils.DebugPrintf("Catalog to add: %+v\n", source)
+
+		fmt.Printf("Fetching apps of %s catalog...\n", source.Name)
+		err := utils.AddCatalogSource(source)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s catalog added successfully\n", source.Name)
+		return nil
+	},
+}
+
+// catalogRemoveCmd represents the catalog remove command
+var catalogRemoveCmd = &cobra.Command{
+	Use:     "remove NAME",
+	Example: "kubemart catalog remove internal",
+	Short:   "Unregister a catalog and delete its applications",
+	Long:    `This command will unregister the catalog and delete its applications. The default catalog can't be removed.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		err := utils.RemoveCatalogSource(name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s catalog removed successfully\n", name)
+		return nil
+	},
+}
+
+// catalogListCmd represents the catalog list command
+var catalogListCmd = &cobra.Command{
+	Use:     "list",
+	Example: "kubemart catalog list",
+	Short:   "List all registered catalogs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sources, err := utils.GetCatalogSources()
+		if err != nil {
+			return err
+		}
+
+		if len(sources) == 0 {
+			fmt.Println("No catalogs found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 15, 0, 1, ' ', tabwriter.TabIndent)
+		fmt.Fprintln(w, "NAME\tURL\tREF\tPINNED AT")
+		for _, source := range sources {
+			url := fmt.Sprintf("\t%s", source.URL)
+			ref := fmt.Sprintf("\t%s", source.Ref)
+			pin := fmt.Sprintf("\t%s", source.Pin)
+			fmt.Fprintln(w, source.Name, url, ref, pin)
+		}
+
+		w.Flush()
+		return nil
+	},
+}
+
+// catalogRepairCmd represents the catalog repair command
+var catalogRepairCmd = &cobra.Command{
+	Use:     "repair",
+	Example: "kubemart catalog repair",
+	Short:   "Re-create broken catalogs and config file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repairs, err := utils.RepairCatalogs()
+		for _, repair := range repairs {
+			fmt.Printf("Repaired: %s\n", repair)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(repairs) == 0 {
+			fmt.Println("Nothing to repair")
+			return nil
+		}
+
+		fmt.Println("Catalogs repaired successfully")
+		return nil
+	},
+}
+
+// catalogExportCmd represents the catalog export command
+var catalogExportCmd = &cobra.Command{
+	Use:     "export FILE",
+	Example: "kubemart catalog export kubemart-catalogs.tar.gz",
+	Short:   "Package all catalogs into a bundle for offline use",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundle, err := utils.ExportCatalogBundle(args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range bundle.Catalogs {
+			fmt.Printf("Exported %s catalog at commit %s\n", entry.Name, entry.Commit)
+		}
+
+		fmt.Printf("Bundle saved to %s\n", args[0])
+		return nil
+	},
+}
+
+// catalogImportCmd represents the catalog import command
+var catalogImportCmd = &cobra.Command{
+	Use:     "import FILE",
+	Example: "kubemart catalog import kubemart-catalogs.tar.gz",
+	Short:   "Replace catalogs with the ones from a bundle",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundle, err := utils.ImportCatalogBundle(args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range bundle.Catalogs {
+			fmt.Printf("Imported %s catalog at commit %s\n", entry.Name, entry.Commit)
+		}
+
+		if !utils.IsOfflineMode() {
+			fmt.Println("Catalogs will be refreshed from the network unless offline mode is enabled ('kubemart catalog offline on')")
+		}
+
+		return nil
+	},
+}
+
+// catalogOfflineCmd represents the catalog offline command
+var catalogOfflineCmd = &cobra.Command{
+	Use:       "offline [on|off]",
+	Example:   "kubemart catalog offline on",
+	Short:     "Show or change whether catalogs are fetched from the network",
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"on", "off"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			status := "off"
+			if utils.IsOfflineMode() {
+				status = "on"
+			}
+			fmt.Printf("Offline mode: %s\n", status)
+			return nil
+		}
+
+		switch args[0] {
+		case "on":
+			err := utils.SetOfflineMode(true)
+			if err != nil {
+				return err
+			}
+		case "off":
+			err := utils.SetOfflineMode(false)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid value %s - supported values are on, off", args[0])
+		}
+
+		fmt.Printf("Offline mode is now %s\n", args[0])
+		return nil
+	},
+}
+
+// catalogPinCmd represents the catalog pin command
+var catalogPinCmd = &cobra.Command{
+	Use:     "pin [COMMIT]",
+	Example: "kubemart catalog pin 1a2b3c4\nkubemart catalog pin 1a2b3c4 --catalog internal\nkubemart catalog pin",
+	Short:   "Hold a catalog at a commit and record it in the lock file",
+	Long: `This command will check out the catalog at the given commit and stop refreshing it.
+The commit is recorded in the lock file, so others can reproduce the same installs.
+When COMMIT is not given, every catalog is pinned at the commit recorded in the lock file.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lock, lockFound, err := utils.ReadLockFile(LockFilePath)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			if !lockFound {
+				return fmt.Errorf("%s file not found - please provide a commit to pin", LockFilePath)
+			}
+
+			for catalogName, commit := range lock.Catalogs {
+				pinned, err := utils.PinCatalog(catalogName, commit)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s catalog is now pinned at %s\n", catalogName, pinned)
+			}
+
+			return nil
+		}
+
+		pinned, err := utils.PinCatalog(CatalogName, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s catalog is now pinned at %s\n", CatalogName, pinned)
+
+		err = lock.RecordCatalogs()
+		if err != nil {
+			return err
+		}
+
+		err = utils.WriteLockFile(LockFilePath, lock)
+		if err != nil {
+			return fmt.Errorf("unable to write %s file - %v", LockFilePath, err)
+		}
+
+		fmt.Printf("%s file updated\n", LockFilePath)
+		return nil
+	},
+}
+
+// catalogUnpinCmd represents the catalog unpin command
+var catalogUnpinCmd = &cobra.Command{
+	Use:     "unpin",
+	Example: "kubemart catalog unpin\nkubemart catalog unpin --catalog internal",
+	Short:   "Let a pinned catalog follow its branch or tag again",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := utils.UnpinCatalog(CatalogName)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s catalog is no longer pinned\n", CatalogName)
+		return nil
+	},
+}
+
+// catalogChangesCmd represents the catalog changes command
+var catalogChangesCmd = &cobra.Command{
+	Use:     "changes",
+	Example: "kubemart catalog changes\nkubemart catalog changes --since 7d\nkubemart catalog changes --since 1a2b3c4 --catalog internal",
+	Short:   "Show what's new in the catalogs",
+	Long: `This command will list the apps that were added, removed or changed (version, plans
+and dependencies) since the last refresh of the catalogs. Use '--since' to compare with
+a commit, or with the catalog as it was some time ago e.g. 12h or 7d.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sources, err := utils.GetCatalogSources()
+		if err != nil {
+			return err
+		}
+
+		if CatalogChangesName != "" {
+			source, err := utils.GetCatalogSource(CatalogChangesName)
+			if err != nil {
+				return err
+			}
+			sources = []utils.CatalogSource{source}
+		}
+
+		since, isDuration := parseSinceDuration(CatalogChangesSince)
+		if CatalogChangesSince != "" && !isDuration && len(sources) > 1 {
+			return fmt.Errorf("please use '--catalog' flag to choose which catalog %s commit belongs to", CatalogChangesSince)
+		}
+
+		for i, source := range sources {
+			var changes *utils.CatalogChanges
+			switch {
+			case CatalogChangesSince == "":
+				changes, err = utils.GetCatalogChangesSinceLastRefresh(source.Name)
+			case isDuration:
+				changes, err = utils.GetCatalogChangesSince(source.Name, since)
+			default:
+				changes, err = utils.GetCatalogChanges(source.Name, CatalogChangesSince)
+			}
+			if err != nil {
+				return err
+			}
+
+			if i > 0 {
+				fmt.Println()
+			}
+			printCatalogChanges(source.Name, changes)
+		}
+
+		return nil
+	},
+}
+
+// catalogOverlayCmd represents the catalog overlay command
+var catalogOverlayCmd = &cobra.Command{
+	Use:     "overlay",
+	Example: "kubemart catalog overlay add ./my-apps\nkubemart catalog overlay list",
+	Short:   "Manage the local directories of apps that take precedence over the catalogs",
+	Long: `An overlay directory contains app folders (e.g. apps in development) laid out like a catalog.
+Its apps can be listed and inspected as if they were in the catalogs. Installing them only
+validates them, nothing is created in the cluster: the operator can only install apps from
+its marketplace.`,
+}
+
+// catalogOverlayAddCmd represents the catalog overlay add command
+var catalogOverlayAddCmd = &cobra.Command{
+	Use:     "add DIRECTORY",
+	Example: "kubemart catalog overlay add ./my-apps",
+	Short:   "Register an overlay directory",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		directory, err := utils.AddOverlayDirectory(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s overlay directory added\n", directory)
+		return nil
+	},
+}
+
+// catalogOverlayRemoveCmd represents the catalog overlay remove command
+var catalogOverlayRemoveCmd = &cobra.Command{
+	Use:     "remove DIRECTORY",
+	Example: "kubemart catalog overlay remove ./my-apps",
+	Short:   "Unregister an overlay directory (the directory itself is kept)",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		directory, err := utils.RemoveOverlayDirectory(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s overlay directory removed\n", directory)
+		return nil
+	},
+}
+
+// catalogOverlayListCmd represents the catalog overlay list command
+var catalogOverlayListCmd = &cobra.Command{
+	Use:     "list",
+	Example: "kubemart catalog overlay list",
+	Short:   "List the overlay directories",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		overlays, err := utils.GetOverlayDirectories()
+		if err != nil {
+			return err
+		}
+
+		if len(overlays) == 0 {
+			fmt.Println("No overlay directories found")
+			return nil
+		}
+
+		for _, overlay := range overlays {
+			fmt.Println(overlay)
+		}
+		return nil
+	},
+}
+
+// catalogLintCmd represents the catalog lint command
+var catalogLintCmd = &cobra.Command{
+	Use:     "lint [PATH]",
+	Example: "kubemart catalog lint\nkubemart catalog lint ./kubemart-apps\nkubemart catalog lint ./kubemart-apps --output json",
+	Short:   "Check the apps of a catalog for mistakes",
+	Long: `This command will validate every app of the catalog in PATH (or every registered catalog
+if PATH is not given): required fields of manifest.yaml, plans configuration, dependencies
+and post_install.md. It exits with non-zero code when errors are found.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if CatalogLintOutput != "text" && CatalogLintOutput != "json" {
+			return fmt.Errorf("unknown output format %s - please use text or json", CatalogLintOutput)
+		}
+
+		paths := args
+		if len(paths) == 0 {
+			sources, err := utils.GetCatalogSources()
+			if err != nil {
+				return err
+			}
+
+			for _, source := range sources {
+				catalogDirPath, err := utils.GetCatalogDirectoryPath(source.Name)
+				if err != nil {
+					return err
+				}
+				paths = append(paths, catalogDirPath)
+			}
+		}
+
+		results := []catalogLintResult{}
+		errorsCount, warningsCount := 0, 0
+		for _, path := range paths {
+			issues, err := utils.LintCatalog(path)
+			if err != nil {
+				return err
+			}
+			results = append(results, catalogLintResult{Path: path, Issues: issues})
+
+			for _, issue := range issues {
+				if issue.Severity == utils.LintSeverityError {
+					errorsCount++
+				} else {
+					warningsCount++
+				}
+			}
+		}
+
+		if CatalogLintOutput == "json" {
+			output, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+		} else {
+			for _, result := range results {
+				for _, issue := range result.Issues {
+					fmt.Printf("%s/%s\n", result.Path, issue)
+				}
+			}
+			fmt.Printf("%d error(s), %d warning(s)\n", errorsCount, warningsCount)
+		}
+
+		if errorsCount > 0 {
+			return fmt.Errorf("catalog lint found %d error(s)", errorsCount)
+		}
+
+		return nil
+	},
+}
+
+// parseSinceDuration returns the time of '--since' value when it's a duration.
+// On top of Go durations e.g. 12h, days are supported e.g. 7d.
+func parseSinceDuration(value string) (time.Time, bool) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil && days >= 0 {
+			return time.Now().AddDate(0, 0, -days), true
+		}
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return time.Time{}, false
+	}
+
+	return time.Now().Add(-duration), true
+}
+
+func printCatalogChanges(catalogName string, changes *utils.CatalogChanges) {
+	if changes == nil {
+		fmt.Printf("%s catalog has not been refreshed yet\n", catalogName)
+		return
+	}
+
+	from := changes.FromCommit
+	if from == "" {
+		from = "the beginning"
+	}
+
+	if changes.IsEmpty() {
+		fmt.Printf("%s catalog: no changes from %s to %s\n", catalogName, from, changes.ToCommit)
+		return
+	}
+
+	fmt.Printf("%s catalog: changes from %s to %s\n", catalogName, from, changes.ToCommit)
+	for _, app := range changes.AddedApps {
+		fmt.Printf("  + %s (new app)\n", app)
+	}
+	for _, app := range changes.RemovedApps {
+		fmt.Printf("  - %s (removed)\n", app)
+	}
+	for _, change := range changes.VersionChanges {
+		fmt.Printf("  ~ %s version: %s -> %s\n", change.App, change.From, change.To)
+	}
+	for _, change := range changes.PlanChanges {
+		fmt.Printf("  ~ %s plans: %s -> %s\n", change.App, orNone(change.From), orNone(change.To))
+	}
+	for _, change := range changes.DependencyChanges {
+		fmt.Printf("  ~ %s dependencies: %s -> %s\n", change.App, orNone(change.From), orNone(change.To))
+	}
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// printInstalledAppsUpdateNotice prints a one-line notice when the last
+// refresh of the catalogs brought a new version of an installed app
+func printInstalledAppsUpdateNotice() {
+	sources, err := utils.GetCatalogSources()
+	if err != nil {
+		return
+	}
+
+	newVersions := make(map[string]string)
+	for _, source := range sources {
+		changes, err := utils.GetCatalogChangesSinceLastRefresh(source.Name)
+		if err != nil {
+			utils.DebugPrintf("Unable to get changes of %s catalog - %v\n", source.Name, err)
+			continue
+		}
+		if changes == nil {
+			continue
+		}
+
+		for _, change := range changes.VersionChanges {
+			if _, found := newVersions[change.App]; !found {
+				newVersions[change.App] = change.To
+			}
+		}
+	}
+
+	if len(newVersions) == 0 {
+		return
+	}
+
+	cs, err := NewClientFromLocalKubeConfig()
+	if err != nil {
+		utils.DebugPrintf("Unable to check installed apps - %v\n", err)
+		return
+	}
+
+	apps, err := cs.ListApps()
+	if err != nil {
+		utils.DebugPrintf("Unable to check installed apps - %v\n", err)
+		return
+	}
+
+	updates := []string{}
+	for _, app := range apps.Items {
+		newVersion, found := newVersions[appCatalogName(&app)]
+		if found && newVersion != app.Status.InstalledVersion {
+			updates = append(updates, fmt.Sprintf("%s %s", app.Name, newVersion))
+		}
+	}
+
+	if len(updates) > 0 {
+		fmt.Fprintf(os.Stderr, "New catalog version for installed apps: %s - see 'kubemart catalog changes'\n", strings.Join(updates, ", "))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(catalogCmd)
+	catalogCmd.AddCommand(catalogAddCmd)
+	catalogCmd.AddCommand(catalogRemoveCmd)
+	catalogCmd.AddCommand(catalogListCmd)
+	catalogCmd.AddCommand(catalogRepairCmd)
+	catalogCmd.AddCommand(catalogExportCmd)
+	catalogCmd.AddCommand(catalogImportCmd)
+	catalogCmd.AddCommand(catalogOfflineCmd)
+	catalogCmd.AddCommand(catalogPinCmd)
+	catalogCmd.AddCommand(catalogUnpinCmd)
+	catalogCmd.AddCommand(catalogChangesCmd)
+	catalogCmd.AddCommand(catalogLintCmd)
+	catalogCmd.AddCommand(catalogOverlayCmd)
+	catalogOverlayCmd.AddCommand(catalogOverlayAddCmd)
+	catalogOverlayCmd.AddCommand(catalogOverlayRemoveCmd)
+	catalogOverlayCmd.AddCommand(catalogOverlayListCmd)
+
+	catalogAddCmd.Flags().StringVarP(&CatalogRef, "ref", "r", "", "Git branch or tag to use (will default to the repository's default branch if not supplied)")
+	catalogPinCmd.Flags().StringVarP(&CatalogName, "catalog", "c", utils.DefaultCatalogName, "catalog to pin")
+	catalogPinCmd.Flags().StringVarP(&LockFilePath, "lockfile", "l", utils.DefaultLockFileName, "lock file to record the commit in")
+	catalogUnpinCmd.Flags().StringVarP(&CatalogName, "catalog", "c", utils.DefaultCatalogName, "catalog to unpin")
+	catalogChangesCmd.Flags().StringVarP(&CatalogChangesName, "catalog", "c", "", "only show changes of this catalog")
+	catalogLintCmd.Flags().StringVarP(&CatalogLintOutput, "output", "o", "text", "output format i.e. text or json")
+	catalogChangesCmd.Flags().StringVar(&CatalogChangesSince, "since", "", "commit or duration (e.g. 12h, 7d) to compare with (will default to the last refresh if not supplied)")
+}
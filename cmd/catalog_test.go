@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestCatalogPinUnpinDefaultCatalog(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, firstHash := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+	secondHash := fixtures.AddCommit(t, sourceDir, "wordpress/manifest.yaml", "version: 1.0.0\n")
+
+	// fresh catalogs, so the commands do not refresh them
+	kp, _ := utils.GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	source := utils.CatalogSource{Name: utils.DefaultCatalogName, URL: sourceDir}
+	_ = utils.WriteConfigFile(&utils.KubemartConfigFile{AppsLastUpdatedAt: time.Now().Unix(), Catalogs: []utils.CatalogSource{source}})
+	_ = utils.CloneCatalog(source)
+
+	lockDir, _ := ioutil.TempDir("", "kubemart-lock")
+	defer os.RemoveAll(lockDir)
+
+	// without '--catalog', the default catalog is pinned and unpinned
+	rootCmd.SetArgs([]string{"catalog", "pin", firstHash, "--lockfile", filepath.Join(lockDir, utils.DefaultLockFileName)})
+	err := rootCmd.Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualHash, _ := utils.GitLatestCommitHash(kp.AppsDirectoryPath)
+	if actualHash != firstHash {
+		t.Errorf("Expected default catalog to be pinned at %s but actual is %s", firstHash, actualHash)
+	}
+
+	rootCmd.SetArgs([]string{"catalog", "unpin"})
+	err = rootCmd.Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualHash, _ = utils.GitLatestCommitHash(kp.AppsDirectoryPath)
+	if actualHash != secondHash {
+		t.Errorf("Expected default catalog to be unpinned at %s but actual is %s", secondHash, actualHash)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -17,9 +18,25 @@ const (
 	baseURL = "/apis/kubemart.civo.com/v1alpha1/namespaces/kubemart-system/apps"
 )
 
+const (
+	// DryRunNone sends the changes to the cluster
+	DryRunNone = "none"
+	// DryRunClient does not send the changes to the cluster
+	DryRunClient = "client"
+	// DryRunServer lets the cluster validate the changes without persisting them
+	DryRunServer = "server"
+)
+
+// AppValuesAnnotation holds every configuration value of the App as a JSON object.
+// It's a record for the CLI only: the operator reads Spec.Plan, which holds the value
+// of the plan's key (see utils.GetAppPlanVariableName), and ignores the other values.
+const AppValuesAnnotation = "kubemart.civo.com/values"
+
 // Clientset is used as receiver object in few functions below
 type Clientset struct {
 	*kubernetes.Clientset
+	// DryRun is one of DryRunNone (or empty), DryRunClient and DryRunServer
+	DryRun string
 }
 
 func checkIfCrdExists() error {
@@ -47,7 +64,7 @@ func NewClientFromLocalKubeConfig() (*Clientset, error) {
 		return &Clientset{}, fmt.Errorf("unable to create k8s clientset - %v", err)
 	}
 
-	return &Clientset{cs}, nil
+	return &Clientset{Clientset: cs}, nil
 }
 
 // NewClientFromKubeConfigString is called by Civo CLI
@@ -63,12 +80,12 @@ func NewClientFromKubeConfigString(kubeconfig string) (*Clientset, error) {
 		return &Clientset{}, err
 	}
 
-	return &Clientset{cs}, nil
+	return &Clientset{Clientset: cs}, nil
 }
 
-// CreateApp will create an App in user's cluster
-func (cs *Clientset) CreateApp(appName string, plan string) (bool, error) {
-	app := &operator.App{
+// NewApp returns the App to create for the given app and plan value
+func NewApp(appName string, plan string) *operator.App {
+	return &operator.App{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "kubemart.civo.com/v1alpha1",
 			Kind:       "App",
@@ -83,22 +100,80 @@ func (cs *Clientset) CreateApp(appName string, plan string) (bool, error) {
 			Plan:   plan,
 		},
 	}
+}
+
+// NewAppWithValues is like NewApp but also records every configuration value of
+// the app e.g. when its plans configure several keys
+func NewAppWithValues(appName string, plan string, values map[string]string) *operator.App {
+	app := NewApp(appName, plan)
+	if len(values) > 0 {
+		// a map of strings can always be marshaled
+		annotation, _ := json.Marshal(values)
+		app.ObjectMeta.Annotations = map[string]string{AppValuesAnnotation: string(annotation)}
+	}
+	return app
+}
+
+// appValues returns the configuration values recorded in the annotation of the App
+func appValues(app *operator.App) (map[string]string, error) {
+	values := make(map[string]string)
+	annotation := app.ObjectMeta.Annotations[AppValuesAnnotation]
+	if annotation == "" {
+		return values, nil
+	}
+
+	err := json.Unmarshal([]byte(annotation), &values)
+	if err != nil {
+		return values, fmt.Errorf("unable to read %s annotation of %s app - %v", AppValuesAnnotation, app.Name, err)
+	}
+	return values, nil
+}
+
+// appCatalogName returns the name of the app in the catalog, the App's name being
+// the instance name
+func appCatalogName(app *operator.App) string {
+	if app.Spec.Name != "" {
+		return app.Spec.Name
+	}
+	return app.Name
+}
+
+// CreateApp will create an App in user's cluster
+func (cs *Clientset) CreateApp(appName string, plan string) (bool, error) {
+	_, created, err := cs.createApp(NewApp(appName, plan))
+	return created, err
+}
+
+// createApp is like CreateApp but also returns the App as created
+func (cs *Clientset) createApp(app *operator.App) (*operator.App, bool, error) {
+	if cs.DryRun == DryRunClient {
+		return app, true, nil
+	}
 
 	body, err := json.Marshal(app)
 	if err != nil {
-		return false, fmt.Errorf("unable to marshall app's manifest - %v", err)
+		return app, false, fmt.Errorf("unable to marshall app's manifest - %v", err)
 	}
 
 	created := false
-	err = cs.RESTClient().
+	result := &operator.App{}
+	err = cs.dryRunParam(cs.RESTClient().
 		Post().
 		AbsPath(baseURL).
-		Body(body).
+		Body(body)).
 		Do(context.Background()).
 		WasCreated(&created).
-		Error()
+		Into(result)
 
-	return created, err
+	return result, created, err
+}
+
+// dryRunParam asks the API server to not persist the request in server dry run mode
+func (cs *Clientset) dryRunParam(request *rest.Request) *rest.Request {
+	if cs.DryRun == DryRunServer {
+		return request.Param("dryRun", metav1.DryRunAll)
+	}
+	return request
 }
 
 // GetApp will get an App from user's cluster
@@ -142,44 +217,116 @@ func (cs *Clientset) ListApps() (*operator.AppList, error) {
 
 // UpdateApp will update an App in user's cluster
 func (cs *Clientset) UpdateApp(appName string) error {
+	_, err := cs.updateApp(appName)
+	return err
+}
+
+// updateApp is like UpdateApp but also returns the App as updated
+func (cs *Clientset) updateApp(appName string) (*operator.App, error) {
 	app, err := cs.GetApp(appName)
 	if err != nil {
-		return err
+		return app, err
 	}
 
 	if !app.ObjectMeta.DeletionTimestamp.IsZero() {
-		return fmt.Errorf("this %s app is being deleted - you can't update it", appName)
+		return app, fmt.Errorf("this %s app is being deleted - you can't update it", appName)
 	}
 
 	if !app.Status.NewUpdateAvailable {
-		return fmt.Errorf("there is no new update available for this app - you are already using the latest version")
+		return app, fmt.Errorf("there is no new update available for this app - you are already using the latest version")
 	}
 
 	app.Spec.Action = "update"
+	if cs.DryRun == DryRunClient {
+		return app, nil
+	}
+
 	body, err := json.Marshal(app)
 	if err != nil {
-		return fmt.Errorf("unable to marshall app's manifest - %v", err)
+		return app, fmt.Errorf("unable to marshall app's manifest - %v", err)
 	}
 
 	path := fmt.Sprintf("%s/%s", baseURL, appName)
-	err = cs.RESTClient().
+	result := &operator.App{}
+	err = cs.dryRunParam(cs.RESTClient().
 		Patch(types.MergePatchType).
 		AbsPath(path).
-		Body(body).
+		Body(body)).
 		Do(context.Background()).
-		Error()
+		Into(result)
 
-	return err
+	return result, err
+}
+
+// patchAppPlan sets the plan of the App, merges the values into the annotation with
+// all its values (the other values recorded before are kept) and schedules the App
+// to be updated
+func (cs *Clientset) patchAppPlan(app *operator.App, plan string, values map[string]string) (*operator.App, error) {
+	app.Spec.Plan = plan
+	app.Spec.Action = "update"
+	if len(values) > 0 {
+		merged, err := appValues(app)
+		if err != nil {
+			return app, err
+		}
+		for key, value := range values {
+			merged[key] = value
+		}
+
+		// a map of strings can always be marshaled
+		annotation, _ := json.Marshal(merged)
+		if app.ObjectMeta.Annotations == nil {
+			app.ObjectMeta.Annotations = make(map[string]string)
+		}
+		app.ObjectMeta.Annotations[AppValuesAnnotation] = string(annotation)
+	}
+
+	if cs.DryRun == DryRunClient {
+		return app, nil
+	}
+
+	body, err := json.Marshal(app)
+	if err != nil {
+		return app, fmt.Errorf("unable to marshall app's manifest - %v", err)
+	}
+
+	path := fmt.Sprintf("%s/%s", baseURL, app.Name)
+	result := &operator.App{}
+	err = cs.dryRunParam(cs.RESTClient().
+		Patch(types.MergePatchType).
+		AbsPath(path).
+		Body(body)).
+		Do(context.Background()).
+		Into(result)
+
+	return result, err
 }
 
 // DeleteApp will delete an App from user's cluster
 func (cs *Clientset) DeleteApp(appName string) error {
+	_, err := cs.deleteApp(appName)
+	return err
+}
+
+// deleteApp is like DeleteApp but also returns the App being deleted
+func (cs *Clientset) deleteApp(appName string) (*operator.App, error) {
+	if cs.DryRun == DryRunClient {
+		return cs.GetApp(appName)
+	}
+
 	path := fmt.Sprintf("%s/%s", baseURL, appName)
-	err := cs.RESTClient().
+	app := &operator.App{}
+	result := cs.dryRunParam(cs.RESTClient().
 		Delete().
-		AbsPath(path).
-		Do(context.Background()).
-		Error()
+		AbsPath(path)).
+		Do(context.Background())
 
-	return err
+	err := result.Error()
+	if err != nil {
+		return app, err
+	}
+
+	// the response may be a Status rather than the App
+	_ = result.Into(app)
+	return app, nil
 }
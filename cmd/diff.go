@@ -0,0 +1,158 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	"github.com/spf13/cobra"
+)
+
+// ExitCodeDrift is used by 'diff' when the cluster does not match the file
+const ExitCodeDrift = 4
+
+const (
+	driftMissing      = "missing"
+	driftExtra        = "extra"
+	driftPlanMismatch = "plan mismatch"
+	driftOutdated     = "outdated"
+)
+
+// appDrift is a difference between the app set file and the cluster
+type appDrift struct {
+	app     string
+	drift   string
+	desired string
+	actual  string
+}
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:     "diff",
+	Example: "kubemart diff -f kubemart.yaml",
+	Short:   "Compare the applications listed in a file with the cluster",
+	Long: fmt.Sprintf(`This command will report the apps of the file that are missing in the cluster, the apps
+that are installed but not in the file (nor needed by the apps in the file), the apps that
+run a different plan than the one in the file and the apps that have a new version.
+It exits with code %d when the cluster does not match the file, e.g. to be used in a CI job.`, ExitCodeDrift),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		appSet, err := utils.ReadAppSetFile(AppSetFilePath)
+		if err != nil {
+			return err
+		}
+
+		cs, err := NewClientFromLocalKubeConfig()
+		if err != nil {
+			return err
+		}
+
+		drifts, err := cs.DiffAppSet(appSet)
+		if err != nil {
+			return err
+		}
+
+		if len(drifts) == 0 {
+			fmt.Println("No drift found, the cluster matches the file")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 15, 0, 1, ' ', tabwriter.TabIndent)
+		fmt.Fprintln(w, "NAME\tDRIFT\tDESIRED\tACTUAL")
+		for _, d := range drifts {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.app, d.drift, d.desired, d.actual)
+		}
+		w.Flush()
+
+		return &exitError{code: ExitCodeDrift, err: fmt.Errorf("drift found in %d app(s)", len(drifts))}
+	},
+}
+
+// DiffAppSet returns the differences between the app set and the apps installed in the cluster
+func (cs *Clientset) DiffAppSet(appSet *utils.KubemartAppSet) ([]appDrift, error) {
+	installedApps, err := cs.ListApps()
+	if err != nil {
+		return nil, err
+	}
+
+	return diffAppSet(appSet, installedApps.Items)
+}
+
+// diffAppSet returns the differences between the app set and the given installed apps
+func diffAppSet(appSet *utils.KubemartAppSet, installedApps []operator.App) ([]appDrift, error) {
+	drifts := []appDrift{}
+	for _, entry := range appSet.Apps {
+		_, name := utils.ParseAppRef(entry.Name)
+		desiredPlan := "-"
+		if entry.Plan != "" {
+			desiredPlan = entry.Plan
+		}
+
+		found := false
+		for _, app := range installedApps {
+			if app.Name != name || !app.DeletionTimestamp.IsZero() {
+				continue
+			}
+			found = true
+
+			if entry.Plan != "" {
+				planValue, err := utils.GetAppPlanValueByLabel(entry.Name, entry.Plan)
+				if err != nil {
+					return nil, err
+				}
+
+				if planValue != app.Spec.Plan {
+					desired := fmt.Sprintf("%s (%s)", entry.Plan, planValue)
+					drifts = append(drifts, appDrift{name, driftPlanMismatch, desired, app.Spec.Plan})
+				}
+			}
+
+			if app.Status.NewUpdateAvailable {
+				drifts = append(drifts, appDrift{name, driftOutdated, app.Status.NewUpdateVersion, app.Status.InstalledVersion})
+			}
+		}
+
+		if !found {
+			drifts = append(drifts, appDrift{name, driftMissing, desiredPlan, "-"})
+		}
+	}
+
+	needed, err := neededApps(appSet)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range installedApps {
+		if !needed[app.Name] {
+			actual := "installed"
+			if !app.DeletionTimestamp.IsZero() {
+				actual = "terminating"
+			}
+			drifts = append(drifts, appDrift{app.Name, driftExtra, "-", actual})
+		}
+	}
+
+	return drifts, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&AppSetFilePath, "filename", "f", utils.DefaultAppSetFileName, "file listing the apps the cluster should have")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffAppSet(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"wordpress/manifest.yaml": "dependencies:\n- mariadb\n",
+		"mariadb/manifest.yaml":   "plans:\n- label: 5GB\n  configuration:\n    VOLUME_SIZE:\n      value: 5Gi\n- label: 10GB\n  configuration:\n    VOLUME_SIZE:\n      value: 10Gi\n",
+		"rabbitmq/manifest.yaml":  "version: 3.8.9\n",
+	})
+	defer cleanup()
+
+	appSet := &utils.KubemartAppSet{Apps: []utils.AppSetEntry{{Name: "wordpress"}, {Name: "mariadb", Plan: "5GB"}}}
+
+	outdated := NewApp("wordpress", "")
+	outdated.Status.InstalledVersion = "5.6"
+	outdated.Status.NewUpdateAvailable = true
+	outdated.Status.NewUpdateVersion = "5.7"
+
+	terminating := NewApp("rabbitmq", "")
+	now := metav1.Now()
+	terminating.DeletionTimestamp = &now
+
+	cases := []struct {
+		name      string
+		installed []operator.App
+		expected  []appDrift
+	}{
+		{
+			name:      "in sync",
+			installed: []operator.App{*NewApp("wordpress", ""), *NewApp("mariadb", "5Gi")},
+			expected:  []appDrift{},
+		},
+		{
+			name:      "missing",
+			installed: []operator.App{*NewApp("wordpress", "")},
+			expected:  []appDrift{{"mariadb", driftMissing, "5GB", "-"}},
+		},
+		{
+			name:      "extra",
+			installed: []operator.App{*NewApp("wordpress", ""), *NewApp("mariadb", "5Gi"), *NewApp("rabbitmq", "")},
+			expected:  []appDrift{{"rabbitmq", driftExtra, "-", "installed"}},
+		},
+		{
+			name:      "terminating",
+			installed: []operator.App{*NewApp("wordpress", ""), *NewApp("mariadb", "5Gi"), *terminating},
+			expected:  []appDrift{{"rabbitmq", driftExtra, "-", "terminating"}},
+		},
+		{
+			name:      "plan mismatch",
+			installed: []operator.App{*NewApp("wordpress", ""), *NewApp("mariadb", "10Gi")},
+			expected:  []appDrift{{"mariadb", driftPlanMismatch, "5GB (5Gi)", "10Gi"}},
+		},
+		{
+			name:      "outdated",
+			installed: []operator.App{*outdated, *NewApp("mariadb", "5Gi")},
+			expected:  []appDrift{{"wordpress", driftOutdated, "5.7", "5.6"}},
+		},
+	}
+
+	for _, c := range cases {
+		drifts, err := diffAppSet(appSet, c.installed)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(drifts, c.expected) {
+			t.Errorf("%s: expected %+v but got %+v", c.name, c.expected, drifts)
+		}
+	}
+}
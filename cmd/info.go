@@ -0,0 +1,152 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubemart/kubemart-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:     "info",
+	Example: "kubemart info APP_NAME\nkubemart info SOURCE/APP_NAME",
+	Short:   "Show the application's details before installing it",
+	Long:    `This command will display everything the catalog knows about an application i.e. its plans, dependencies and notes. It does not need a cluster.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return RunInfo(args[0])
+	},
+}
+
+// RunInfo prints the details of the app from the local catalogs
+func RunInfo(appRef string) error {
+	catalogName, appDirPath, err := utils.ResolveApp(appRef)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := utils.GetAppManifest(appRef)
+	if err != nil {
+		return err
+	}
+
+	if catalogName == utils.OverlayCatalogName {
+		catalogName = fmt.Sprintf("%s (%s)", catalogName, filepath.Dir(appDirPath))
+	}
+
+	refCatalogName, appName := utils.ParseAppRef(appRef)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", appName)
+	fmt.Fprintf(w, "Catalog:\t%s\n", catalogName)
+	fmt.Fprintf(w, "Version:\t%s\n", manifest.Version)
+	fmt.Fprintf(w, "Category:\t%s\n", manifest.Category)
+	fmt.Fprintf(w, "Namespace:\t%s\n", manifest.Namespace)
+	if manifest.Description != "" {
+		fmt.Fprintf(w, "Description:\t%s\n", manifest.Description)
+	}
+	w.Flush()
+
+	fmt.Println("\nPlans:")
+	if len(manifest.Plans) == 0 {
+		fmt.Println("  This app does not have any plans")
+	}
+	for _, plan := range manifest.Plans {
+		fmt.Printf("  %s\n", plan.Label)
+
+		keys := []string{}
+		for key := range plan.Configuration {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Printf("    %s=%s\n", key, plan.Configuration[key].Value)
+		}
+	}
+
+	fmt.Println("\nDependencies:")
+	if len(manifest.Dependencies) == 0 {
+		fmt.Println("  This app does not have any dependencies")
+	}
+	printDependencyTree(manifest.Dependencies, refCatalogName, "  ", map[string]bool{appName: true})
+
+	readme, err := utils.GetReadmeMarkdown(appRef)
+	if err != nil {
+		return err
+	}
+	if readme != "" {
+		fmt.Println("\nREADME:")
+		fmt.Println(readme)
+	}
+
+	postInstall, err := utils.GetPostInstallMarkdown(appRef)
+	if err == nil {
+		fmt.Println("\nPost-install notes:")
+		fmt.Println(postInstall)
+	} else {
+		utils.DebugPrintf("Unable to load post-install notes - %v\n", err)
+	}
+
+	return nil
+}
+
+// printDependencyTree prints the dependencies and their own dependencies, indented.
+// Like the install, dependencies are looked up in the dependent's catalog first.
+// The path holds the apps above in the tree, to stop on circular dependencies.
+func printDependencyTree(dependencies []string, catalogName, indent string, path map[string]bool) {
+	for _, dependency := range dependencies {
+		name, plan := utils.ParseDependency(dependency)
+		label := name
+		if plan != "" {
+			label = fmt.Sprintf("%s (plan: %s)", name, plan)
+		}
+
+		if path[name] {
+			fmt.Printf("%s%s - circular dependency\n", indent, label)
+			continue
+		}
+
+		dependencyRef := name
+		if catalogName != "" && utils.IsAppExist(fmt.Sprintf("%s/%s", catalogName, name)) {
+			dependencyRef = fmt.Sprintf("%s/%s", catalogName, name)
+		}
+
+		manifest, err := utils.GetAppManifest(dependencyRef)
+		if err != nil {
+			fmt.Printf("%s%s - not found in catalogs\n", indent, label)
+			continue
+		}
+
+		fmt.Printf("%s%s %s\n", indent, label, manifest.Version)
+
+		dependencyCatalogName, _ := utils.ParseAppRef(dependencyRef)
+		path[name] = true
+		printDependencyTree(manifest.Dependencies, dependencyCatalogName, indent+strings.Repeat(" ", 2), path)
+		delete(path, name)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	"github.com/kubemart/kubemart-cli/test"
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestInfoWithoutClusterNorNetwork(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	overlayDir := fixtures.WriteFiles(t, map[string]string{
+		"wordpress/manifest.yaml": "version: 5.7\nnamespace: wordpress\ndependencies:\n- mariadb:5GB\n- redis\n",
+		"mariadb/manifest.yaml":   "version: 10.5\nnamespace: mariadb\ndependencies:\n- wordpress\n",
+	})
+	defer os.RemoveAll(overlayDir)
+
+	// stale catalogs that can't be refreshed, like offline
+	kp, _ := utils.GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	source := utils.CatalogSource{Name: "internal", URL: "/nonexistent/kubemart-apps"}
+	_ = utils.WriteConfigFile(&utils.KubemartConfigFile{Catalogs: []utils.CatalogSource{source}, Overlays: []string{overlayDir}})
+
+	var err error
+	out, errOut := test.RecordStdOutStdErr(func() {
+		rootCmd.SetArgs([]string{"info", "wordpress"})
+		err = rootCmd.Execute()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(errOut, "unable to refresh the catalogs") {
+		t.Errorf("Expected a warning about the catalogs refresh but got %q", errOut)
+	}
+
+	expected := []string{
+		"Version:   5.7",
+		"Catalog:   overlay (" + overlayDir + ")",
+		"  mariadb (plan: 5GB) 10.5\n    wordpress - circular dependency\n",
+		"  redis - not found in catalogs\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected %q in info output but got:\n%s", e, out)
+		}
+	}
+}
@@ -17,39 +17,286 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/forestgiant/sliceutil"
 	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
+// IgnoreLock is used to install even if local catalogs do not match the lock file
+var IgnoreLock bool
+
+// InstallFrom is the local app folder to install from
+var InstallFrom string
+
+// InstallName is the name of the App to create, to install several instances of an app
+var InstallName string
+
+// InstallNamespace is rejected: the operator installs every app into the namespace of its manifest
+var InstallNamespace string
+
+// InstallUpgradeIfExists is used to update the apps already installed when a new version is available
+var InstallUpgradeIfExists bool
+
+// InstallReinstall is used to delete and create again the apps already installed
+var InstallReinstall bool
+
+// InstallParallel is the maximum number of apps created at the same time
+var InstallParallel int
+
+// InstallAtomic is used to delete the apps created by the install when any app fails
+var InstallAtomic bool
+
+// InstallPlan picks the plan of the apps given without one i.e. smallest, largest or a plan label
+var InstallPlan string
+
+// InstallSetValues are the KEY=VALUE pairs overriding the configuration of the plan
+var InstallSetValues []string
+
+// InstallValuesFilePath is a YAML file overriding the configuration of the plan
+var InstallValuesFilePath string
+
 // installCmd represents the install command
 var installCmd = &cobra.Command{
-	Use:     "install APP_NAME[:PLAN]",
-	Example: "kubemart install rabbitmq\nkubemart install wordpress:10GB,linkerd:\"Linkerd with Dashboard\"",
+	Use:     "install [SOURCE/]APP_NAME[:PLAN]",
+	Example: "kubemart install rabbitmq\nkubemart install wordpress:10GB,linkerd:\"Linkerd with Dashboard\"\nkubemart install internal/wordpress:10GB\nkubemart install --from ./my-app --plan largest\nkubemart install mariadb:10GB --set VOLUME_SIZE=15Gi\nkubemart install wordpress,mariadb --plan largest\nkubemart install redis --name cache-a",
 	Short:   "Install application(s)",
-	Args:    cobra.MinimumNArgs(1),
+	Long: `This command will install the application(s) onto the Kubernetes cluster.
+
+Use '--from' to check an app from a local folder (e.g. while developing it) as if it
+was in the catalog: the folder is validated just like 'kubemart catalog lint', then its
+dependencies and plan are resolved against the catalogs and the Apps that would be created
+are shown. Nothing is created in the cluster, since the kubemart operator installs the
+apps from its own marketplace and can't fetch the local folder. The apps of an overlay
+directory (see 'kubemart catalog overlay') are only validated the same way.
+
+On a terminal, the command is interactive: it lets you search the app when none is
+given, pick the plan of the apps given without one and confirm the installation.
+Use '--non-interactive' to turn it off e.g. in scripts.
+
+The plan sets every configuration key its app declares. Use '--set KEY=VALUE' or
+'--values file.yaml' to override the value of the plan key e.g. VOLUME_SIZE, '--set' wins
+over the file. The operator only reads the plan key, so the other keys declared by the
+app (see 'kubemart info APP_NAME') can't be overridden - choose another plan instead.
+
+Apps that do not depend on each other are created at the same time (see '--parallel').
+When some apps fail, the others are still created and the command exits with code 2.
+Use '--atomic' to delete the apps created by the command instead, the apps reinstalled
+with '--reinstall' are kept.
+
+Use '--name' to install an app more than once, each instance is then addressed by its
+name in the other commands e.g. 'kubemart uninstall cache-a'. Every instance is installed
+into the namespace of the app, as the operator does not support another namespace, so
+the instances may collide unless the app supports running side by side.
+
+Apps already installed are left as they are, so the command can be run again safely.
+Use '--upgrade-if-exists' to update them when a new version is available, or
+'--reinstall' to delete them and create them again.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if InstallFrom != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+
+		if !isInteractive() {
+			return cobra.MinimumNArgs(1)(cmd, args)
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		err := validateDryRunAndOutputFlags()
+		if err != nil {
+			return err
+		}
+
+		if InstallUpgradeIfExists && InstallReinstall {
+			return fmt.Errorf("--upgrade-if-exists and --reinstall can't be used together")
+		}
+
+		if InstallFrom != "" {
+			appName, err := prepareLocalApp(InstallFrom)
+			if err != nil {
+				return err
+			}
+			args = []string{appName}
+		}
+
+		if len(args) == 0 {
+			manifests, err := GetAppManifestsMap()
+			if err != nil {
+				return err
+			}
+
+			appName, err := pickApp(promptInput, os.Stdout, manifests)
+			if err != nil {
+				return err
+			}
+			args = []string{appName}
+		}
+
 		cs, err := NewClientFromLocalKubeConfig()
 		if err != nil {
 			return err
 		}
+		cs.DryRun = DryRun
 
-		processedAppsAndPlanLabels, err := cs.PreRunInstall(cmd, args)
+		requestedApps, err := cs.PreRunInstall(cmd, args)
 		if err != nil {
 			return err
 		}
 
-		err = cs.RunInstall(processedAppsAndPlanLabels)
+		values, err := utils.ParseValues(InstallSetValues, InstallValuesFilePath)
 		if err != nil {
 			return err
 		}
 
+		if InstallNamespace != "" {
+			return fmt.Errorf("--namespace is not supported - the operator installs every app into the namespace of its manifest")
+		}
+
+		if InstallName != "" && len(requestedApps) != 1 {
+			return fmt.Errorf("--name can only be used when installing a single app")
+		}
+
+		if InstallName != "" {
+			errs := validation.IsDNS1123Subdomain(InstallName)
+			if len(errs) > 0 {
+				return fmt.Errorf("%s is not a valid name - %s", InstallName, strings.Join(errs, ", "))
+			}
+			requestedApps[0].InstanceName = InstallName
+		}
+
+		if len(values) > 0 {
+			if len(requestedApps) != 1 {
+				return fmt.Errorf("--set and --values can only be used when installing a single app")
+			}
+
+			err = utils.ValidateAppValues(requestedApps[0].Name, values)
+			if err != nil {
+				return err
+			}
+			requestedApps[0].Values = values
+		}
+
+		apps, err := cs.ResolveInstallOrder(requestedApps)
+		if err != nil {
+			return err
+		}
+
+		validateOnly, err := cs.validateOnlyLocalApps(apps)
+		if err != nil {
+			return err
+		}
+
+		appNames := []string{}
+		for _, app := range apps {
+			appNames = append(appNames, app.Name)
+		}
+
+		lock, lockFound, err := utils.ReadLockFile(LockFilePath)
+		if err != nil {
+			return err
+		}
+
+		useLock := lockFound && !IgnoreLock
+		if useLock {
+			err = lock.Verify(appNames)
+			if err != nil {
+				errMsg := "%v\nYou can run 'kubemart catalog pin' to match the lock file or use '--ignore-lock' flag to install anyway"
+				return fmt.Errorf(errMsg, err)
+			}
+		}
+
+		if isInteractive() && !validateOnly {
+			proceed, err := confirm(promptInput, os.Stdout, fmt.Sprintf("Install %d app(s)?", len(apps)))
+			if err != nil {
+				return err
+			}
+
+			if !proceed {
+				return fmt.Errorf("operation cancelled")
+			}
+		}
+
+		err = cs.RunInstall(apps)
+		if err != nil {
+			return err
+		}
+
+		if useLock && dryRunSuffix() == "" {
+			err = lock.RecordApps(appNames)
+			if err != nil {
+				return err
+			}
+
+			err = utils.WriteLockFile(LockFilePath, lock)
+			if err != nil {
+				return fmt.Errorf("unable to update %s file - %v", LockFilePath, err)
+			}
+		}
+
 		return nil
 	},
 }
 
+// validateOnlyLocalApps switches to a client dry run when some apps come from a local
+// folder or an overlay directory: the operator can only install apps from its marketplace,
+// so creating them would install the marketplace version instead. It returns true then.
+func (cs *Clientset) validateOnlyLocalApps(apps []utils.AppToInstall) (bool, error) {
+	localApps := []string{}
+	for _, app := range apps {
+		isLocal, err := utils.IsOverlayApp(app.Name)
+		if err != nil {
+			return false, err
+		}
+		if isLocal {
+			localApps = append(localApps, app.Name)
+		}
+	}
+
+	if len(localApps) == 0 {
+		return false, nil
+	}
+
+	if DryRun == DryRunNone {
+		DryRun = DryRunClient
+	}
+	cs.DryRun = DryRun
+
+	fmt.Fprintf(messages(), "%s app(s) come from a local folder or an overlay, so they are only validated - nothing will be created in the cluster, the operator can only install apps from its marketplace\n", strings.Join(localApps, ", "))
+	return true, nil
+}
+
+// prepareLocalApp validates the app folder and makes it available to install.
+// It returns the app name.
+func prepareLocalApp(appDirPath string) (string, error) {
+	manifests, err := GetAppManifestsMap()
+	if err != nil {
+		return "", err
+	}
+
+	issues, err := utils.LintApp(appDirPath, manifests)
+	if err != nil {
+		return "", err
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintln(messages(), issue)
+	}
+
+	if utils.HasLintErrors(issues) {
+		return "", fmt.Errorf("%s app is not valid - please fix the errors above", appDirPath)
+	}
+
+	return utils.AddLocalApp(appDirPath)
+}
+
 func (cs *Clientset) HasTerminatingDependency(appName string) ([]string, bool) {
 	terminatingApps := []string{}
 	appManifest, err := utils.GetAppManifest(appName)
@@ -72,8 +319,9 @@ func (cs *Clientset) HasTerminatingDependency(appName string) ([]string, bool) {
 	return terminatingApps, false
 }
 
-func (cs *Clientset) PreRunInstall(cmd *cobra.Command, args []string) (map[string]string, error) {
-	appsAndPlanLabels := make(map[string]string)
+// PreRunInstall parses the apps and plans given by the user, in the given order
+func (cs *Clientset) PreRunInstall(cmd *cobra.Command, args []string) ([]utils.AppToInstall, error) {
+	appsAndPlanLabels := []utils.AppToInstall{}
 
 	appsCombined := args[0]
 	apps := strings.Split(appsCombined, ",")
@@ -93,16 +341,25 @@ func (cs *Clientset) PreRunInstall(cmd *cobra.Command, args []string) (map[strin
 			appPlanLabel = splitted[1]
 		}
 
-		appsAndPlanLabels[appName] = appPlanLabel
+		appsAndPlanLabels = append(appsAndPlanLabels, utils.AppToInstall{Name: appName, PlanLabel: appPlanLabel})
 	}
 
-	processedAppsAndPlanLabels := make(map[string]string)
-	for appName, planLabel := range appsAndPlanLabels {
+	processedAppsAndPlanLabels := []utils.AppToInstall{}
+	for _, app := range appsAndPlanLabels {
+		appName, planLabel := app.Name, app.PlanLabel
 		appExists := utils.IsAppExist(appName)
 		if !appExists {
 			return appsAndPlanLabels, fmt.Errorf("unable to find %s app", appName)
 		}
 
+		if planLabel == "" && InstallPlan != "" {
+			selectedPlan, err := utils.SelectAppPlan(appName, InstallPlan)
+			if err != nil {
+				return appsAndPlanLabels, err
+			}
+			planLabel = selectedPlan
+		}
+
 		appPlanLabels, err := utils.GetAppPlans(appName)
 		if err != nil {
 			return appsAndPlanLabels, fmt.Errorf("unable to list app's plans - %v", err)
@@ -110,10 +367,20 @@ func (cs *Clientset) PreRunInstall(cmd *cobra.Command, args []string) (map[strin
 
 		if len(appPlanLabels) > 0 {
 			firstPlan := utils.GetSmallestAppPlan(appPlanLabels)
-			if planLabel == "" {
+			if planLabel == "" && isInteractive() {
+				manifest, err := utils.GetAppManifest(appName)
+				if err != nil {
+					return appsAndPlanLabels, fmt.Errorf("unable to load %s app manifest - %v", appName, err)
+				}
+
+				planLabel, err = pickPlan(promptInput, os.Stdout, appName, manifest)
+				if err != nil {
+					return appsAndPlanLabels, err
+				}
+			} else if planLabel == "" {
 				planLabel = firstPlan
-				fmt.Printf("This %s app require a plan. Next time you could use '%s APP_NAME[:PLAN]' format.\n", appName, cmd.CommandPath())
-				fmt.Printf("Since the plan is not present, this %s installation will proceed with the smallest one (%s).\n", appName, planLabel)
+				fmt.Fprintf(messages(), "This %s app require a plan. Next time you could use '%s APP_NAME[:PLAN]' format or the '--plan' flag.\n", appName, cmd.CommandPath())
+				fmt.Fprintf(messages(), "Since the plan is not present, this %s installation will proceed with the smallest one (%s).\n", appName, planLabel)
 			}
 
 			if !sliceutil.Contains(appPlanLabels, planLabel) {
@@ -121,42 +388,455 @@ func (cs *Clientset) PreRunInstall(cmd *cobra.Command, args []string) (map[strin
 			}
 		}
 
-		processedAppsAndPlanLabels[appName] = planLabel
+		processedAppsAndPlanLabels = append(processedAppsAndPlanLabels, utils.AppToInstall{Name: appName, PlanLabel: planLabel})
 		utils.DebugPrintf("Plan to proceed with for %s app: %s\n", appName, planLabel)
 	}
 
 	return processedAppsAndPlanLabels, nil
 }
 
-func (cs *Clientset) RunInstall(processedAppsAndPlanLabels map[string]string) error {
-	createdApps := []string{}
+// ResolveInstallOrder adds the dependencies that are not installed yet to the
+// requested apps, dependencies first, and shows the result to the user
+func (cs *Clientset) ResolveInstallOrder(requestedApps []utils.AppToInstall) ([]utils.AppToInstall, error) {
+	installedApps, err := cs.ListApps()
+	if err != nil {
+		return nil, err
+	}
 
-	for appName, appPlan := range processedAppsAndPlanLabels {
-		if appPlan != "" {
-			plan, err := utils.GetAppPlanValueByLabel(appName, appPlan)
-			if err != nil {
-				return err
+	// a dependency is installed when there is an instance of it, whatever its name
+	installed := make(map[string]bool)
+	for _, app := range installedApps.Items {
+		installed[appCatalogName(&app)] = true
+	}
+
+	apps, skipped, err := utils.ResolveDependencies(requestedApps, func(appName string) bool {
+		return installed[appName]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the order is always shown in interactive mode, to be confirmed
+	if len(apps) > len(requestedApps) || isInteractive() {
+		fmt.Fprintln(messages(), "The following apps will be created, in this order:")
+		for _, app := range apps {
+			line := fmt.Sprintf("  %s", app.Name)
+			if app.PlanLabel != "" {
+				line += fmt.Sprintf(" (plan: %s)", app.PlanLabel)
+			}
+			if len(app.RequiredBy) > 0 {
+				line += fmt.Sprintf(" - required by %s", strings.Join(app.RequiredBy, ", "))
 			}
-			appPlan = plan
+			fmt.Fprintln(messages(), line)
 		}
+	}
+
+	if len(skipped) > 0 {
+		fmt.Fprintf(messages(), "Dependencies already installed: %s\n", strings.Join(skipped, ", "))
+	}
+
+	return apps, nil
+}
+
+// newAppToCreate returns the App for the app, configured with every value of its
+// plan and the values given by the user on top, named after the instance name if any
+func newAppToCreate(app utils.AppToInstall) (*operator.App, error) {
+	_, name := utils.ParseAppRef(app.Name)
 
-		created, err := cs.CreateApp(appName, appPlan)
-		if !created {
-			return fmt.Errorf("%s app creation failed - %+v", appName, err.Error())
-		} else {
-			createdApps = append(createdApps, appName)
+	values := make(map[string]string)
+	if app.PlanLabel != "" {
+		configuration, err := utils.GetAppPlanConfiguration(app.Name, app.PlanLabel)
+		if err != nil {
+			return nil, err
+		}
+		values = configuration
+	}
+
+	for key, value := range app.Values {
+		values[key] = value
+	}
+
+	object := NewApp(name, "")
+	if len(values) > 0 {
+		planKey, err := utils.GetAppPlanVariableName(app.Name)
+		if err != nil {
+			return nil, err
+		}
+		object = NewAppWithValues(name, values[planKey], values)
+	}
+
+	// Spec.Name stays the app of the catalog
+	if app.InstanceName != "" {
+		object.ObjectMeta.Name = app.InstanceName
+	}
+
+	return object, nil
+}
+
+const (
+	installCreated          = "created"
+	installAlreadyExists    = "already exists"
+	installAlreadyInstalled = "already installed"
+	installUpdated          = "update scheduled"
+	installReinstalled      = "reinstalled"
+	installFailed           = "failed"
+	installSkipped          = "skipped"
+	installRolledBack       = "rolled back"
+)
+
+// installResult is the outcome of the creation of an app
+type installResult struct {
+	app    utils.AppToInstall
+	status string
+	reason string
+	object *operator.App
+}
+
+// isFailed returns true when the app is not in the cluster as asked
+func (r installResult) isFailed() bool {
+	return r.status == installFailed || r.status == installSkipped || r.status == installAlreadyExists
+}
+
+// otherInstances returns the names of the installed instances of the app, other than
+// the one to install. They all share the namespace of the app.
+func otherInstances(app utils.AppToInstall, installedApps []operator.App) []string {
+	_, appName := utils.ParseAppRef(app.Name)
+	instanceName := app.InstanceName
+	if instanceName == "" {
+		instanceName = appName
+	}
+
+	instances := []string{}
+	for i := range installedApps {
+		if appCatalogName(&installedApps[i]) == appName && installedApps[i].Name != instanceName {
+			instances = append(instances, installedApps[i].Name)
+		}
+	}
+	return instances
+}
+
+// RunInstall creates the apps, the independent ones at the same time. The apps that
+// fail do not stop the others, except the apps depending on them. With '--atomic',
+// the apps created by this run are deleted when any app fails. Apps already in the
+// cluster are left as they are, unless '--upgrade-if-exists' or '--reinstall' is used.
+func (cs *Clientset) RunInstall(apps []utils.AppToInstall) error {
+	installedApps, err := cs.ListApps()
+	if err != nil {
+		return err
+	}
+
+	existingApps := make(map[string]*operator.App)
+	for i := range installedApps.Items {
+		existingApps[installedApps.Items[i].Name] = &installedApps.Items[i]
+	}
+
+	for _, app := range apps {
+		instances := otherInstances(app, installedApps.Items)
+		if len(instances) > 0 {
+			_, appName := utils.ParseAppRef(app.Name)
+			fmt.Fprintf(messages(), "Warning: %s app is already installed as %s, in the same namespace - the instances may collide\n", appName, strings.Join(instances, ", "))
+		}
+	}
+
+	results := createAppsInParallel(apps, InstallParallel, func(app utils.AppToInstall) installResult {
+		toCreate, err := newAppToCreate(app)
+		if err != nil {
+			return installResult{status: installFailed, reason: err.Error()}
+		}
+
+		existing, found := existingApps[toCreate.Name]
+		if found {
+			return cs.installExistingApp(existing, toCreate)
+		}
+
+		return installResultOf(cs.createApp(toCreate))
+	})
+
+	// only the apps created from scratch are rolled back, the reinstalled ones were
+	// already in the cluster before
+	created, failures := 0, 0
+	for _, result := range results {
+		switch {
+		case result.isFailed():
+			failures++
+		case result.status == installCreated:
+			created++
+		}
+	}
+
+	if InstallAtomic && failures > 0 && created > 0 {
+		err := cs.rollbackInstall(results)
+		if err != nil {
+			printInstallResults(results)
+			return err
+		}
+	}
+
+	createdApps := []string{}
+	reinstalledApps := []string{}
+	processedObjects := []*operator.App{}
+	checks := make(map[string]appCheck)
+	for _, result := range results {
+		switch result.status {
+		case installCreated, installReinstalled:
+			createdApp := result.app.Name
+			if result.app.InstanceName != "" {
+				createdApp = fmt.Sprintf("%s as %s", result.app.Name, result.app.InstanceName)
+			}
+			if result.status == installCreated {
+				createdApps = append(createdApps, createdApp)
+			} else {
+				reinstalledApps = append(reinstalledApps, createdApp)
+			}
+			processedObjects = append(processedObjects, result.object)
+			checks[result.object.Name] = installCheck
+		case installUpdated:
+			processedObjects = append(processedObjects, result.object)
+			checks[result.object.Name] = updateCheck(result.object.Status.NewUpdateVersion, result.object.Status.LastStatus)
+		case installAlreadyInstalled:
+			fmt.Fprintf(messages(), "%s app is already installed (%s)\n", result.object.Name, result.reason)
 		}
 	}
 
 	if len(createdApps) > 0 {
-		fmt.Printf("App(s) created successfully: %s\n", strings.Join(createdApps, ", "))
+		fmt.Fprintf(messages(), "App(s) created successfully%s: %s\n", dryRunSuffix(), strings.Join(createdApps, ", "))
+	}
+
+	if len(reinstalledApps) > 0 {
+		fmt.Fprintf(messages(), "App(s) reinstalled successfully%s: %s\n", dryRunSuffix(), strings.Join(reinstalledApps, ", "))
+	}
+
+	if len(results) > 1 || failures > 0 {
+		printInstallResults(results)
+	}
+
+	err = printApps(processedObjects)
+	if err != nil {
+		return err
+	}
+
+	if Wait && dryRunSuffix() == "" && len(processedObjects) > 0 {
+		names := []string{}
+		for _, object := range processedObjects {
+			names = append(names, object.Name)
+		}
+
+		err = cs.WaitForApps(names, WaitTimeout, func(eventType watch.EventType, app *operator.App) (bool, error) {
+			return checks[app.Name](eventType, app)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if failures == 0 {
+		return nil
 	}
 
+	err = fmt.Errorf("%d of %d app(s) could not be created", failures, len(results))
+	if failures < len(results) && !(InstallAtomic && created > 0) {
+		return &exitError{code: ExitCodePartialFailure, err: err}
+	}
+	return err
+}
+
+// installExistingApp handles an app that is already in the cluster: it's updated with
+// '--upgrade-if-exists' when a new version is available, deleted and created again
+// with '--reinstall', and left as it is otherwise
+func (cs *Clientset) installExistingApp(existing *operator.App, toCreate *operator.App) installResult {
+	if InstallReinstall {
+		_, err := cs.deleteApp(existing.Name)
+		if err != nil {
+			return installResult{status: installFailed, reason: fmt.Sprintf("unable to delete it - %v", err)}
+		}
+
+		// the App is not deleted in dry run, so it can't be created again
+		if dryRunSuffix() != "" {
+			return installResult{status: installReinstalled, object: toCreate}
+		}
+
+		err = cs.WaitForApps([]string{existing.Name}, WaitTimeout, uninstallCheck)
+		if err != nil {
+			return installResult{status: installFailed, reason: fmt.Sprintf("unable to delete it - %v", err)}
+		}
+
+		result := installResultOf(cs.createApp(toCreate))
+		if result.status == installCreated {
+			result.status = installReinstalled
+		}
+		return result
+	}
+
+	if !existing.ObjectMeta.DeletionTimestamp.IsZero() {
+		return installResult{status: installFailed, reason: "it is being deleted - please try again later or use '--reinstall'"}
+	}
+
+	if InstallUpgradeIfExists && existing.Status.NewUpdateAvailable {
+		object, err := cs.updateApp(existing.Name)
+		if err != nil {
+			return installResult{status: installFailed, reason: fmt.Sprintf("unable to update it - %v", err)}
+		}
+
+		// the checks need the status before the update
+		object.Status = existing.Status
+		return installResult{status: installUpdated, reason: fmt.Sprintf("%s to %s", existing.Status.InstalledVersion, existing.Status.NewUpdateVersion), object: object}
+	}
+
+	reason := fmt.Sprintf("version %s, status %s", orNone(existing.Status.InstalledVersion), orNone(existing.Status.LastStatus))
+	return installResult{status: installAlreadyInstalled, reason: reason, object: existing}
+}
+
+// installResultOf returns the result of createApp
+func installResultOf(object *operator.App, created bool, err error) installResult {
+	switch {
+	case apierrors.IsAlreadyExists(err):
+		return installResult{status: installAlreadyExists, reason: err.Error()}
+	case err != nil:
+		return installResult{status: installFailed, reason: err.Error()}
+	case !created:
+		return installResult{status: installFailed, reason: "the cluster did not create it"}
+	}
+	return installResult{status: installCreated, object: object}
+}
+
+// createAppsInParallel calls create for every app, with at most workers calls at the
+// same time. An app is only created once the apps it depends on (in the given apps)
+// are in the cluster, and skipped when one of them fails. Results are in the order of apps.
+func createAppsInParallel(apps []utils.AppToInstall, workers int, create func(app utils.AppToInstall) installResult) []installResult {
+	results := make([]installResult, len(apps))
+
+	indexes := make(map[string]int)
+	for i, app := range apps {
+		_, name := utils.ParseAppRef(app.Name)
+		indexes[name] = i
+	}
+
+	// the apps each app depends on, among the given apps
+	dependencies := make([][]int, len(apps))
+	for i, app := range apps {
+		for _, dependency := range app.DependsOn {
+			j, found := indexes[dependency]
+			if found {
+				dependencies[i] = append(dependencies[i], j)
+			}
+		}
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	done := make(chan int)
+	started := make([]bool, len(apps))
+	finished := make([]bool, len(apps))
+	finishedCount, running := 0, 0
+
+	for finishedCount < len(apps) {
+		for i, app := range apps {
+			if started[i] || running >= workers {
+				continue
+			}
+
+			ready, failedDependency := true, ""
+			for _, j := range dependencies[i] {
+				if !finished[j] {
+					ready = false
+					break
+				}
+
+				if results[j].status == installFailed || results[j].status == installSkipped {
+					failedDependency = apps[j].Name
+				}
+			}
+
+			if !ready {
+				continue
+			}
+
+			started[i] = true
+			if failedDependency != "" {
+				results[i] = installResult{app: app, status: installSkipped, reason: fmt.Sprintf("%s app could not be created", failedDependency)}
+				finished[i] = true
+				finishedCount++
+				continue
+			}
+
+			running++
+			go func(i int, app utils.AppToInstall) {
+				results[i] = create(app)
+				results[i].app = app
+				done <- i
+			}(i, app)
+		}
+
+		if running == 0 {
+			// all apps are finished, or the remaining ones wait for each other
+			break
+		}
+
+		i := <-done
+		running--
+		finished[i] = true
+		finishedCount++
+	}
+
+	for i, app := range apps {
+		if !started[i] {
+			results[i] = installResult{app: app, status: installSkipped, reason: "circular dependency"}
+		}
+	}
+
+	return results
+}
+
+// rollbackInstall deletes the created apps, dependents first
+func (cs *Clientset) rollbackInstall(results []installResult) error {
+	for i := len(results) - 1; i >= 0; i-- {
+		if results[i].status != installCreated {
+			continue
+		}
+
+		name := results[i].object.Name
+		_, err := cs.deleteApp(name)
+		if err != nil {
+			return fmt.Errorf("unable to roll back %s app - %v", name, err)
+		}
+
+		results[i].status = installRolledBack
+	}
+
+	fmt.Fprintf(messages(), "The apps created by this install were deleted because some apps failed%s\n", dryRunSuffix())
 	return nil
 }
 
+// printInstallResults prints the outcome of every app
+func printInstallResults(results []installResult) {
+	w := tabwriter.NewWriter(messages(), 15, 0, 1, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "NAME\tRESULT\tREASON")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.app.Name, result.status, result.reason)
+	}
+	w.Flush()
+}
+
 func init() {
 	rootCmd.AddCommand(installCmd)
+	installCmd.Flags().StringVarP(&LockFilePath, "lockfile", "l", utils.DefaultLockFileName, "lock file to check the catalogs against (ignored when it does not exist)")
+	installCmd.Flags().BoolVar(&IgnoreLock, "ignore-lock", false, "install even if the catalogs do not match the lock file")
+	installCmd.Flags().StringVar(&InstallFrom, "from", "", "local app folder to check as if it was in the catalog (nothing is created in the cluster)")
+	addDryRunAndOutputFlags(installCmd)
+	addWaitFlags(installCmd)
+	installCmd.Flags().StringVar(&InstallName, "name", "", "name of the app instance (defaults to the app name), to install the same app several times")
+	installCmd.Flags().StringVar(&InstallNamespace, "namespace", "", "not supported, the operator installs every app into the namespace of its manifest")
+	_ = installCmd.Flags().MarkHidden("namespace")
+	installCmd.Flags().BoolVar(&InstallUpgradeIfExists, "upgrade-if-exists", false, "update the apps already installed when a new version is available")
+	installCmd.Flags().BoolVar(&InstallReinstall, "reinstall", false, "delete the apps already installed and create them again")
+	installCmd.Flags().IntVar(&InstallParallel, "parallel", 4, "maximum number of apps created at the same time")
+	installCmd.Flags().BoolVar(&InstallAtomic, "atomic", false, "delete the apps created by this install if any app fails (reinstalled and updated apps are kept)")
+	installCmd.Flags().StringVar(&InstallPlan, "plan", "", "plan of the apps given without one: smallest, largest or a plan label")
+	installCmd.Flags().StringArrayVar(&InstallSetValues, "set", []string{}, "override the plan key's value, in KEY=VALUE format (other keys are not supported by the operator)")
+	installCmd.Flags().StringVar(&InstallValuesFilePath, "values", "", "YAML file with the plan key's value overriding the plan's one")
+	installCmd.Flags().BoolVar(&NonInteractive, "non-interactive", false, "never prompt, even on a terminal")
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/forestgiant/sliceutil"
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCreateAppsInParallel(t *testing.T) {
+	apps := []utils.AppToInstall{
+		{Name: "longhorn", RequiredBy: []string{"mariadb"}},
+		{Name: "mariadb", RequiredBy: []string{"wordpress"}, DependsOn: []string{"longhorn"}},
+		{Name: "wordpress", DependsOn: []string{"mariadb"}},
+		{Name: "linkerd"},
+		{Name: "rabbitmq"},
+	}
+
+	var mutex sync.Mutex
+	created := []string{}
+	results := createAppsInParallel(apps, 2, func(app utils.AppToInstall) installResult {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch app.Name {
+		case "mariadb":
+			return installResultOf(nil, false, fmt.Errorf("connection refused"))
+		case "rabbitmq":
+			return installResultOf(nil, false, apierrors.NewAlreadyExists(schema.GroupResource{Group: "kubemart.civo.com", Resource: "apps"}, "rabbitmq"))
+		}
+
+		created = append(created, app.Name)
+		return installResultOf(NewApp(app.Name, ""), true, nil)
+	})
+
+	expected := map[string]string{
+		"longhorn":  installCreated,
+		"mariadb":   installFailed,
+		"wordpress": installSkipped,
+		"linkerd":   installCreated,
+		"rabbitmq":  installAlreadyExists,
+	}
+	for i, result := range results {
+		if result.app.Name != apps[i].Name {
+			t.Errorf("Expected results in the order of the apps but got %s at %d", result.app.Name, i)
+		}
+
+		if result.status != expected[result.app.Name] {
+			t.Errorf("Expected %s app to be %s but got %s (%s)", result.app.Name, expected[result.app.Name], result.status, result.reason)
+		}
+	}
+
+	if results[1].reason != "connection refused" {
+		t.Errorf("Expected the reason of the failure but got %s", results[1].reason)
+	}
+
+	if len(created) != 2 {
+		t.Errorf("Expected longhorn and linkerd apps to be created but got %v", created)
+	}
+}
+
+func TestCreateAppsInParallelWaitsForDependencies(t *testing.T) {
+	apps := []utils.AppToInstall{
+		{Name: "longhorn", RequiredBy: []string{"mariadb", "minio"}},
+		{Name: "mariadb", RequiredBy: []string{"wordpress"}, DependsOn: []string{"longhorn"}},
+		{Name: "minio", DependsOn: []string{"longhorn"}},
+		{Name: "wordpress", DependsOn: []string{"mariadb"}},
+	}
+
+	var mutex sync.Mutex
+	created := make(map[string]bool)
+	results := createAppsInParallel(apps, 4, func(app utils.AppToInstall) installResult {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		for _, dependency := range app.DependsOn {
+			if !created[dependency] {
+				return installResultOf(nil, false, fmt.Errorf("%s app was created before %s app", app.Name, dependency))
+			}
+		}
+
+		created[app.Name] = true
+		return installResultOf(NewApp(app.Name, ""), true, nil)
+	})
+
+	for _, result := range results {
+		if result.status != installCreated {
+			t.Errorf("Expected %s app to be created but got %s (%s)", result.app.Name, result.status, result.reason)
+		}
+	}
+}
+
+func TestCreateAppsInParallelWaitsForRequestedDependencies(t *testing.T) {
+	// both apps are requested by the user, so neither is required by the other
+	apps := []utils.AppToInstall{
+		{Name: "wordpress", DependsOn: []string{"mariadb"}},
+		{Name: "mariadb"},
+	}
+
+	var mutex sync.Mutex
+	created := []string{}
+	results := createAppsInParallel(apps, 2, func(app utils.AppToInstall) installResult {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if app.Name == "wordpress" && !sliceutil.Contains(created, "mariadb") {
+			return installResultOf(nil, false, fmt.Errorf("wordpress app was created before mariadb app"))
+		}
+
+		created = append(created, app.Name)
+		return installResultOf(NewApp(app.Name, ""), true, nil)
+	})
+
+	for _, result := range results {
+		if result.status != installCreated {
+			t.Errorf("Expected %s app to be created but got %s (%s)", result.app.Name, result.status, result.reason)
+		}
+	}
+
+	if len(created) != 2 || created[0] != "mariadb" {
+		t.Errorf("Expected mariadb app to be created before wordpress app but got %v", created)
+	}
+}
+
+func TestNewAppToCreateInstance(t *testing.T) {
+	app, err := newAppToCreate(utils.AppToInstall{Name: "redis", InstanceName: "cache-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if app.Name != "cache-a" || app.Spec.Name != "redis" || appCatalogName(app) != "redis" {
+		t.Errorf("Expected cache-a instance of redis app but got %s instance of %s app", app.Name, app.Spec.Name)
+	}
+}
+
+func TestOtherInstances(t *testing.T) {
+	cacheA := NewApp("cache-a", "")
+	cacheA.Spec.Name = "redis"
+	installed := []operator.App{*NewApp("redis", ""), *cacheA, *NewApp("mariadb", "")}
+
+	instances := otherInstances(utils.AppToInstall{Name: "redis", InstanceName: "cache-b"}, installed)
+	if strings.Join(instances, ",") != "redis,cache-a" {
+		t.Errorf("Expected redis and cache-a instances but got %v", instances)
+	}
+
+	instances = otherInstances(utils.AppToInstall{Name: "internal/redis"}, installed)
+	if strings.Join(instances, ",") != "cache-a" {
+		t.Errorf("Expected cache-a instance but got %v", instances)
+	}
+
+	instances = otherInstances(utils.AppToInstall{Name: "rabbitmq"}, installed)
+	if len(instances) != 0 {
+		t.Errorf("Expected no other instance but got %v", instances)
+	}
+}
+
+func TestInstallExistingApp(t *testing.T) {
+	cs := &Clientset{DryRun: DryRunClient}
+	existing := NewApp("rabbitmq", "")
+	existing.Status.InstalledVersion = "3.8.9"
+	existing.Status.LastStatus = "installation_finished"
+
+	result := cs.installExistingApp(existing, NewApp("rabbitmq", ""))
+	if result.status != installAlreadyInstalled || result.reason != "version 3.8.9, status installation_finished" {
+		t.Errorf("Expected rabbitmq app to be already installed but got %s (%s)", result.status, result.reason)
+	}
+	if result.isFailed() {
+		t.Error("Expected an app already installed not to be a failure")
+	}
+}
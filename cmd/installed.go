@@ -53,7 +53,7 @@ func (cs *Clientset) RunInstalled() error {
 	haveTerminatingApps := false
 
 	w := tabwriter.NewWriter(os.Stdout, 15, 0, 1, ' ', tabwriter.TabIndent)
-	fmt.Fprintln(w, "NAME\tCURRENT STATUS\tVERSION\tUPDATE AVAILABLE")
+	fmt.Fprintln(w, "NAME\tAPP\tCURRENT STATUS\tVERSION\tUPDATE AVAILABLE")
 	for _, app := range apps.Items {
 		currentStatus := fmt.Sprintf("\t%s", app.Status.LastStatus)
 		if !app.DeletionTimestamp.IsZero() {
@@ -72,7 +72,8 @@ func (cs *Clientset) RunInstalled() error {
 		}
 		newUpdate := fmt.Sprintf("\t%s", updateAvailable)
 
-		fmt.Fprintln(w, app.Name, currentStatus, version, newUpdate)
+		catalogName := fmt.Sprintf("\t%s", appCatalogName(&app))
+		fmt.Fprintln(w, app.Name, catalogName, currentStatus, version, newUpdate)
 		haveSomething = true
 	}
 
@@ -67,29 +67,76 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// appsDirectory is a folder of apps i.e. a catalog or an overlay directory
+type appsDirectory struct {
+	// source is the catalog name, it's empty for overlay directories
+	source string
+	path   string
+}
+
+// GetAppManifestsMap returns manifests of all apps from all overlay directories and
+// catalogs. The map key is the name to use when installing the app i.e. APP_NAME, or
+// SOURCE/APP_NAME when an earlier catalog (or overlay) already has an app with the same name.
 func GetAppManifestsMap() (map[string]utils.AppManifest, error) {
 	manifests := make(map[string]utils.AppManifest)
 	excludeList = make(map[string]bool)
 	excludeList["bin"] = true
+	seen := make(map[string]bool)
 
-	dir, _ := utils.GetKubemartPaths()
-	path := dir.AppsDirectoryPath
-	files, err := ioutil.ReadDir(path)
+	sources, err := utils.GetCatalogSources()
 	if err != nil {
-		return manifests, fmt.Errorf("unable to get list of files - %v", err)
+		return manifests, fmt.Errorf("unable to load catalogs - %v", err)
+	}
+
+	overlays, err := utils.GetOverlayDirectories()
+	if err != nil {
+		return manifests, err
+	}
+
+	// overlays come first, like when installing
+	directories := []appsDirectory{}
+	for _, overlay := range overlays {
+		directories = append(directories, appsDirectory{path: overlay})
 	}
 
-	for _, file := range files {
-		fileName := file.Name()
-		filePath := fmt.Sprintf("%s/%s", path, fileName)
-		fileInfo, err := os.Stat(filePath)
+	for _, source := range sources {
+		path, err := utils.GetCatalogDirectoryPath(source.Name)
 		if err != nil {
-			return manifests, fmt.Errorf("unable to locate file - %v", err)
+			return manifests, err
 		}
-		if fileInfo.IsDir() && isValid(fileName) {
-			manifest, err := utils.GetAppManifest(fileName)
-			if err == nil {
-				manifests[fileName] = manifest
+		directories = append(directories, appsDirectory{source: source.Name, path: path})
+	}
+
+	for _, directory := range directories {
+		files, err := ioutil.ReadDir(directory.path)
+		if err != nil {
+			return manifests, fmt.Errorf("unable to get list of files - %v", err)
+		}
+
+		for _, file := range files {
+			fileName := file.Name()
+			filePath := fmt.Sprintf("%s/%s", directory.path, fileName)
+			fileInfo, err := os.Stat(filePath)
+			if err != nil {
+				return manifests, fmt.Errorf("unable to locate file - %v", err)
+			}
+			if fileInfo.IsDir() && isValid(fileName) {
+				appRef := fileName
+				if seen[fileName] {
+					// apps of an overlay can't be referred to by source
+					if directory.source == "" {
+						continue
+					}
+					appRef = fmt.Sprintf("%s/%s", directory.source, fileName)
+				}
+				seen[fileName] = true
+
+				manifest, err := utils.GetAppManifest(appRef)
+				if err != nil {
+					utils.DebugPrintf("Skipping %s app, 'kubemart catalog lint' may tell why - %v\n", appRef, err)
+					continue
+				}
+				manifests[appRef] = manifest
 			}
 		}
 	}
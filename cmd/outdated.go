@@ -0,0 +1,189 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	version "github.com/hashicorp/go-version"
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/tcnksm/go-latest"
+)
+
+// ExitCodeOutdated is used by 'outdated' when an app, the operator or the CLI has a new version
+const ExitCodeOutdated = 3
+
+// OutdatedOutput is the output format of 'outdated' i.e. text or json
+var OutdatedOutput string
+
+// outdatedComponent is something with a new version, in json format
+type outdatedComponent struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+}
+
+// outdatedReport is the output of 'outdated', in json format
+type outdatedReport struct {
+	Outdated   []outdatedComponent `json:"outdated"`
+	Unverified []string            `json:"unverified,omitempty"`
+}
+
+// outdatedCmd represents the outdated command
+var outdatedCmd = &cobra.Command{
+	Use:     "outdated",
+	Example: "kubemart outdated\nkubemart outdated -o json",
+	Short:   "List the applications, operator and CLI that have a new version",
+	Long: fmt.Sprintf(`This command will list the installed apps with a new version, and check if the
+kubemart operator and this CLI are the latest release (except in offline mode).
+It exits with code %d when anything is outdated, e.g. to be used in a CI job.`, ExitCodeOutdated),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OutdatedOutput != "text" && OutdatedOutput != "json" {
+			return fmt.Errorf("unknown output format %s - please use text or json", OutdatedOutput)
+		}
+
+		cs, err := NewClientFromLocalKubeConfig()
+		if err != nil {
+			return err
+		}
+
+		report, err := cs.GetOutdatedReport()
+		if err != nil {
+			return err
+		}
+
+		if OutdatedOutput == "json" {
+			output, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+		} else if len(report.Outdated) > 0 {
+			w := tabwriter.NewWriter(os.Stdout, 15, 0, 1, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "NAME\tKIND\tCURRENT\tLATEST")
+			for _, component := range report.Outdated {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", component.Name, component.Kind, component.Current, component.Latest)
+			}
+			w.Flush()
+		} else {
+			fmt.Println("Everything is up to date")
+		}
+
+		if OutdatedOutput == "text" {
+			for _, unverified := range report.Unverified {
+				fmt.Fprintf(os.Stderr, "Unable to check %s\n", unverified)
+			}
+		}
+
+		if len(report.Outdated) > 0 {
+			return &exitError{code: ExitCodeOutdated, err: fmt.Errorf("%d component(s) outdated", len(report.Outdated))}
+		}
+		return nil
+	},
+}
+
+// GetOutdatedReport returns the installed apps that have a new version, then the operator
+// and the CLI when they are not the latest release. The checks that could not be done
+// (e.g. no network) are reported as unverified.
+func (cs *Clientset) GetOutdatedReport() (*outdatedReport, error) {
+	apps, err := cs.ListApps()
+	if err != nil {
+		return nil, err
+	}
+
+	return outdatedReportOf(apps.Items), nil
+}
+
+// outdatedReportOf is GetOutdatedReport for the given installed apps
+func outdatedReportOf(apps []operator.App) *outdatedReport {
+	report := &outdatedReport{Outdated: []outdatedComponent{}}
+	for _, app := range apps {
+		if app.Status.NewUpdateAvailable && app.DeletionTimestamp.IsZero() {
+			report.Outdated = append(report.Outdated, outdatedComponent{app.Name, "app", app.Status.InstalledVersion, app.Status.NewUpdateVersion})
+		}
+	}
+
+	if utils.IsOfflineMode() {
+		report.Unverified = append(report.Unverified, "operator version (offline mode)", "CLI version (offline mode)")
+		return report
+	}
+
+	operatorVersion, err := utils.GetInstalledOperatorVersion()
+	if err != nil {
+		report.Unverified = append(report.Unverified, fmt.Sprintf("installed operator version - %v", err))
+	} else {
+		latestOperatorVersion, err := utils.GetLatestOperatorReleaseVersion()
+		if err == nil && latestOperatorVersion == "" {
+			err = fmt.Errorf("no release found")
+		}
+
+		if err != nil {
+			report.Unverified = append(report.Unverified, fmt.Sprintf("latest operator version - %v", err))
+		} else {
+			outdated, err := isOlderVersion(operatorVersion, latestOperatorVersion)
+			if err != nil {
+				report.Unverified = append(report.Unverified, fmt.Sprintf("operator version - %v", err))
+			} else if outdated {
+				report.Outdated = append(report.Outdated, outdatedComponent{"kubemart-operator", "operator", operatorVersion, latestOperatorVersion})
+			}
+		}
+	}
+
+	githubTag := &latest.GithubTag{
+		Owner:             "kubemart",
+		Repository:        "kubemart-cli",
+		FixVersionStrFunc: latest.DeleteFrontV(),
+	}
+	res, err := latest.Check(githubTag, strings.Replace(VersionCli, "v", "", 1))
+	if err == nil {
+		if res.Outdated {
+			report.Outdated = append(report.Outdated, outdatedComponent{"kubemart", "cli", VersionCli, res.Current})
+		}
+	} else {
+		report.Unverified = append(report.Unverified, fmt.Sprintf("latest CLI version - %v", err))
+	}
+
+	return report
+}
+
+// isOlderVersion returns true when current is an older version than latest, compared
+// semantically like 'latest.Check' does e.g. "v0.0.9" is older than "0.0.10"
+func isOlderVersion(current, latest string) (bool, error) {
+	currentVersion, err := version.NewVersion(current)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse %s version - %v", current, err)
+	}
+
+	latestVersion, err := version.NewVersion(latest)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse %s version - %v", latest, err)
+	}
+
+	return currentVersion.LessThan(latestVersion), nil
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().StringVarP(&OutdatedOutput, "output", "o", "text", "output format i.e. text or json")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOutdatedReportOfApps(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{})
+	defer cleanup()
+
+	err := utils.SetOfflineMode(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outdated := NewApp("wordpress", "")
+	outdated.Status.InstalledVersion = "5.6"
+	outdated.Status.NewUpdateAvailable = true
+	outdated.Status.NewUpdateVersion = "5.7"
+
+	upToDate := NewApp("mariadb", "")
+	upToDate.Status.InstalledVersion = "10.5"
+
+	terminating := NewApp("rabbitmq", "")
+	terminating.Status.InstalledVersion = "3.8.8"
+	terminating.Status.NewUpdateAvailable = true
+	terminating.Status.NewUpdateVersion = "3.8.9"
+	now := metav1.Now()
+	terminating.DeletionTimestamp = &now
+
+	report := outdatedReportOf([]operator.App{*outdated, *upToDate, *terminating})
+
+	if len(report.Outdated) != 1 || report.Outdated[0] != (outdatedComponent{"wordpress", "app", "5.6", "5.7"}) {
+		t.Errorf("Expected only wordpress app to be outdated but got %+v", report.Outdated)
+	}
+
+	if strings.Join(report.Unverified, ", ") != "operator version (offline mode), CLI version (offline mode)" {
+		t.Errorf("Expected the operator and CLI versions to be unverified in offline mode but got %v", report.Unverified)
+	}
+}
+
+func TestIsOlderVersion(t *testing.T) {
+	versions := []struct {
+		current  string
+		latest   string
+		expected bool
+	}{
+		{"v0.0.9", "v0.0.10", true},
+		{"0.0.69", "v0.0.69", false},
+		{"v0.1.0", "0.0.70", false},
+		{"1.2", "1.2.1", true},
+	}
+
+	for _, v := range versions {
+		actual, err := isOlderVersion(v.current, v.latest)
+		if err != nil || actual != v.expected {
+			t.Errorf("Expected %s older than %s to be %t but got %t (%v)", v.current, v.latest, v.expected, actual, err)
+		}
+	}
+
+	_, err := isOlderVersion("latest", "v0.0.69")
+	if err == nil {
+		t.Error("Expected an error when the version can't be parsed")
+	}
+}
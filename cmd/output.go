@@ -0,0 +1,113 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// DryRun is used to not persist the changes, see DryRunClient and DryRunServer
+var DryRun string
+
+// OutputFormat is the format (yaml or json) to print the App objects in
+var OutputFormat string
+
+// addDryRunAndOutputFlags adds '--dry-run' and '--output' flags to the command
+func addDryRunAndOutputFlags(cmd *cobra.Command) {
+	addDryRunFlag(cmd)
+	cmd.Flags().StringVarP(&OutputFormat, "output", "o", "", "print the App objects in yaml or json format")
+}
+
+// addDryRunFlag adds '--dry-run' flag to the command
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&DryRun, "dry-run", DryRunNone, "'client' to only print the changes, 'server' to let the cluster validate them without persisting anything")
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = DryRunClient
+}
+
+// validateDryRunAndOutputFlags returns an error for unknown values of '--dry-run' and '--output' flags
+func validateDryRunAndOutputFlags() error {
+	if DryRun != DryRunNone && DryRun != DryRunClient && DryRun != DryRunServer {
+		return fmt.Errorf("unknown dry run mode %s - please use %s, %s or %s", DryRun, DryRunNone, DryRunClient, DryRunServer)
+	}
+
+	if OutputFormat != "" && OutputFormat != "yaml" && OutputFormat != "json" {
+		return fmt.Errorf("unknown output format %s - please use yaml or json", OutputFormat)
+	}
+
+	return nil
+}
+
+// messages returns where to print messages for the user. When App objects
+// are printed (e.g. to be saved into a file), messages go to stderr.
+func messages() io.Writer {
+	if OutputFormat != "" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// dryRunSuffix returns the text to append to messages in dry run mode
+func dryRunSuffix() string {
+	if DryRun == DryRunClient || DryRun == DryRunServer {
+		return fmt.Sprintf(" (dry run: %s)", DryRun)
+	}
+	return ""
+}
+
+// printApps prints the App objects in the format of '--output' flag. Multiple
+// objects are printed as a YAML stream, or a JSON list.
+func printApps(apps []*operator.App) error {
+	switch OutputFormat {
+	case "yaml":
+		for i, app := range apps {
+			out, err := yaml.Marshal(app)
+			if err != nil {
+				return fmt.Errorf("unable to marshall %s app - %v", app.Name, err)
+			}
+
+			if i > 0 {
+				fmt.Println("---")
+			}
+			fmt.Print(string(out))
+		}
+	case "json":
+		var object interface{} = apps
+		if len(apps) == 1 {
+			object = apps[0]
+		} else {
+			object = map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "List",
+				"items":      apps,
+			}
+		}
+
+		out, err := json.MarshalIndent(object, "", "    ")
+		if err != nil {
+			return fmt.Errorf("unable to marshall apps - %v", err)
+		}
+		fmt.Println(string(out))
+	}
+
+	return nil
+}
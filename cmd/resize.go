@@ -0,0 +1,141 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forestgiant/sliceutil"
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	"github.com/spf13/cobra"
+)
+
+// ResizeForce is used to move an app to a smaller plan
+var ResizeForce bool
+
+// resizeCmd represents the resize command
+var resizeCmd = &cobra.Command{
+	Use:     "resize APP_NAME:PLAN",
+	Example: "kubemart resize wordpress:20GB\nkubemart resize mariadb:5GB --force",
+	Short:   "Change the plan of an installed application",
+	Long: `This command will move the installed application to another plan of its catalog.
+
+Moving to a smaller storage plan may lose data, so it's refused unless '--force' is used.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		splitted := strings.SplitN(args[0], ":", 2)
+		if len(splitted) != 2 || splitted[0] == "" || splitted[1] == "" {
+			return fmt.Errorf("please provide the app and its new plan in APP_NAME:PLAN format")
+		}
+
+		err := validateDryRunAndOutputFlags()
+		if err != nil {
+			return err
+		}
+
+		cs, err := NewClientFromLocalKubeConfig()
+		if err != nil {
+			return err
+		}
+		cs.DryRun = DryRun
+
+		return cs.RunResize(splitted[0], splitted[1])
+	},
+}
+
+// RunResize moves the installed app to the plan with the given label
+func (cs *Clientset) RunResize(appName, planLabel string) error {
+	app, err := cs.GetApp(appName)
+	if err != nil {
+		return fmt.Errorf("unable to find %s app in the cluster - %v", appName, err)
+	}
+
+	if !app.ObjectMeta.DeletionTimestamp.IsZero() {
+		return fmt.Errorf("this %s app is being deleted - you can't resize it", appName)
+	}
+
+	// the App may be an instance of the app with another name
+	catalogName := appCatalogName(app)
+	planLabels, err := utils.GetAppPlans(catalogName)
+	if err != nil {
+		return fmt.Errorf("unable to list app's plans - %v", err)
+	}
+
+	if len(planLabels) == 0 {
+		return fmt.Errorf("%s app does not have any plans", appName)
+	}
+
+	if !sliceutil.Contains(planLabels, planLabel) {
+		return fmt.Errorf("the given plan is not supported for %s app - supported values are %v", appName, strings.Join(planLabels, ", "))
+	}
+
+	target, err := newAppToCreate(utils.AppToInstall{Name: catalogName, PlanLabel: planLabel})
+	if err != nil {
+		return err
+	}
+
+	if target.Spec.Plan == app.Spec.Plan {
+		fmt.Fprintf(messages(), "%s app is already using %s plan\n", appName, planLabel)
+		return nil
+	}
+
+	if isPlanDownsize(app.Spec.Plan, target.Spec.Plan) && !ResizeForce {
+		return fmt.Errorf("moving %s app from %s to %s may lose data - use '--force' to do it anyway", appName, app.Spec.Plan, target.Spec.Plan)
+	}
+
+	values, err := appValues(target)
+	if err != nil {
+		return err
+	}
+
+	resized, err := cs.patchAppPlan(app, target.Spec.Plan, values)
+	if err != nil {
+		return fmt.Errorf("unable to resize app - %v", err)
+	}
+
+	fmt.Fprintf(messages(), "%s app is now scheduled to move to %s plan%s\n", appName, planLabel, dryRunSuffix())
+	err = printApps([]*operator.App{resized})
+	if err != nil {
+		return err
+	}
+
+	if Wait && dryRunSuffix() == "" {
+		return cs.WaitForApps([]string{appName}, WaitTimeout, planCheck(resized.Spec.Plan, resized.ResourceVersion))
+	}
+
+	return nil
+}
+
+// isPlanDownsize returns true if both plan values are sizes (e.g. "5Gi") and the
+// target one is smaller
+func isPlanDownsize(currentPlan, targetPlan string) bool {
+	current, currentIsSize := utils.ParsePlanSize(currentPlan)
+	target, targetIsSize := utils.ParsePlanSize(targetPlan)
+	if !currentIsSize || !targetIsSize {
+		return false
+	}
+
+	return target < current
+}
+
+func init() {
+	rootCmd.AddCommand(resizeCmd)
+	resizeCmd.Flags().BoolVar(&ResizeForce, "force", false, "move to a smaller plan even if it may lose data")
+	addDryRunAndOutputFlags(resizeCmd)
+	addWaitFlags(resizeCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import "testing"
+
+func TestIsPlanDownsize(t *testing.T) {
+	cases := []struct {
+		current  string
+		target   string
+		expected bool
+	}{
+		{"20Gi", "5Gi", true},
+		{"5Gi", "20Gi", false},
+		{"10Gi", "10Gi", false},
+		{"1Ti", "500Gi", true},
+		{"500Gi", "1Ti", false},
+		{"Linkerd with Dashboard", "Linkerd", false},
+	}
+
+	for _, c := range cases {
+		actual := isPlanDownsize(c.current, c.target)
+		if actual != c.expected {
+			t.Errorf("Expected %v when moving from %s to %s but got %v", c.expected, c.current, c.target, actual)
+		}
+	}
+}
+
+func TestPatchAppPlanMergesValues(t *testing.T) {
+	cs := &Clientset{DryRun: DryRunClient}
+	app := NewAppWithValues("mariadb", "5Gi", map[string]string{"VOLUME_SIZE": "5Gi", "REPLICAS": "2"})
+
+	resized, err := cs.patchAppPlan(app, "20Gi", map[string]string{"VOLUME_SIZE": "20Gi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, _ := appValues(resized)
+	if resized.Spec.Plan != "20Gi" || values["VOLUME_SIZE"] != "20Gi" || values["REPLICAS"] != "2" {
+		t.Errorf("Expected 20Gi plan with the other values kept but got %s plan and %v", resized.Spec.Plan, values)
+	}
+
+	resized, _ = cs.patchAppPlan(NewApp("mariadb", "5Gi"), "20Gi", map[string]string{})
+	if _, found := resized.ObjectMeta.Annotations[AppValuesAnnotation]; found {
+		t.Errorf("Expected no values annotation but got %v", resized.ObjectMeta.Annotations)
+	}
+}
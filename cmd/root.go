@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -25,8 +26,26 @@ import (
 
 var kubeCfgFile string
 var debug bool
+var offline bool
 var canSkipUpdateApps map[string]bool
 
+// canUseStaleApps are the commands that only read the catalogs, so they still run
+// (e.g. offline) when the catalogs can't be refreshed
+var canUseStaleApps = map[string]bool{"info": true}
+
+// ExitCodePartialFailure is used when only some of the apps could be processed
+const ExitCodePartialFailure = 2
+
+// exitError is an error that sets the exit code of the command
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use: "kubemart",
@@ -35,18 +54,32 @@ var rootCmd = &cobra.Command{
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		canSkipUpdateApps = make(map[string]bool)
 		canSkipUpdateApps["destroy"] = true
+		canSkipUpdateApps["export"] = true
 		canSkipUpdateApps["help"] = true
+		canSkipUpdateApps["import"] = true
 		canSkipUpdateApps["init"] = true
+		canSkipUpdateApps["lint"] = true
+		canSkipUpdateApps["offline"] = true
+		canSkipUpdateApps["repair"] = true
 		canSkipUpdateApps["system-upgrade"] = true
 		canSkipUpdateApps["version"] = true
 
 		_, found := canSkipUpdateApps[cmd.Name()]
 		if !found {
+			lastUpdated := utils.GetConfigFileLastUpdatedTimestamp()
 			ok, err := utils.UpdateAppsCacheIfStale()
-			if !ok {
+			if !ok && !canUseStaleApps[cmd.Name()] {
 				fmt.Printf("%v\n", err)
 				os.Exit(1)
 			}
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Warning: unable to refresh the catalogs, they may be outdated - %v\n", err)
+			}
+
+			refreshed := utils.GetConfigFileLastUpdatedTimestamp() != lastUpdated
+			if refreshed && cmd.Name() != "changes" {
+				printInstalledAppsUpdateNotice()
+			}
 		}
 	},
 }
@@ -55,6 +88,10 @@ var rootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -66,6 +103,7 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVarP(&kubeCfgFile, "kubeconfig", "k", "", "kubeconfig file")
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "print verbose logs when running command")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "use local catalogs only, without fetching anything from the network")
 	rootCmd.SetHelpCommand(&cobra.Command{Use: "no-help", Hidden: true}) // disable "kubemart help <command>"
 
 	// https://github.com/spf13/cobra/issues/340
@@ -79,6 +117,7 @@ func init() {
 	// we won't see debug statement in other `OnInitialize` functions.
 	cobra.OnInitialize(setLogLevelEnvIfFlagIsTrue)
 	cobra.OnInitialize(replaceKubeconfigEnvIfFlagIsPresent)
+	cobra.OnInitialize(setOfflineEnvIfFlagIsTrue)
 }
 
 // replaceKubeconfigEnvIfFlagIsPresent will set KUBECONFIG env variable
@@ -98,3 +137,11 @@ func setLogLevelEnvIfFlagIsTrue() {
 		os.Setenv("LOGLEVEL", "debug")
 	}
 }
+
+// setOfflineEnvIfFlagIsTrue will set KUBEMART_OFFLINE env variable
+// when user use '--offline' flag
+func setOfflineEnvIfFlagIsTrue() {
+	if offline {
+		os.Setenv("KUBEMART_OFFLINE", "true")
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/kubemart/kubemart-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// SearchCategory is used to only show apps from this category
+var SearchCategory string
+
+// SearchHasPlans is used to only show apps that have plans
+var SearchHasPlans bool
+
+// maxDescriptionLength is the number of characters of the description shown in search results
+const maxDescriptionLength = 60
+
+// searchResult is an app matching the search query
+type searchResult struct {
+	name                 string
+	manifest             utils.AppManifest
+	score                int
+	namePositions        []int
+	categoryPositions    []int
+	descriptionPositions []int
+}
+
+// searchCell is a table cell with the positions of the characters to highlight
+type searchCell struct {
+	text      string
+	positions []int
+}
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:     "search [QUERY]",
+	Example: "kubemart search wordpress\nkubemart search db --category database\nkubemart search --has-plans",
+	Short:   "Search the applications that can be installed",
+	Long:    `This command will rank the applications by how well their name, category and description match the query`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := ""
+		if len(args) > 0 {
+			query = args[0]
+		}
+
+		manifests, err := GetAppManifestsMap()
+		if err != nil {
+			return err
+		}
+
+		results := searchApps(query, manifests)
+		if len(results) == 0 {
+			fmt.Println("No apps found")
+			return nil
+		}
+
+		rows := [][]searchCell{}
+		for _, result := range results {
+			planz := []string{}
+			for _, plan := range result.manifest.Plans {
+				planz = append(planz, plan.Label)
+			}
+
+			description, descriptionPositions := truncate(result.manifest.Description, result.descriptionPositions, maxDescriptionLength)
+			rows = append(rows, []searchCell{
+				{text: result.name, positions: result.namePositions},
+				{text: result.manifest.Version},
+				{text: result.manifest.Category, positions: result.categoryPositions},
+				{text: strings.Join(planz, ", ")},
+				{text: description, positions: descriptionPositions},
+			})
+		}
+
+		printSearchTable([]string{"NAME", "VERSION", "CATEGORY", "PLANS", "DESCRIPTION"}, rows)
+		return nil
+	},
+}
+
+// searchApps returns apps matching the query and filters, best matches first.
+// When the query is empty, all apps matching the filters are returned by name.
+func searchApps(query string, manifests map[string]utils.AppManifest) []searchResult {
+	results := []searchResult{}
+
+	for name, manifest := range manifests {
+		if SearchCategory != "" && !strings.EqualFold(manifest.Category, SearchCategory) {
+			continue
+		}
+
+		if SearchHasPlans && len(manifest.Plans) == 0 {
+			continue
+		}
+
+		result := searchResult{name: name, manifest: manifest}
+		if query != "" {
+			// name matters most, then category, then description
+			nameScore, namePositions := utils.FuzzyMatch(query, name)
+			categoryScore, categoryPositions := utils.FuzzyMatch(query, manifest.Category)
+			descriptionScore, descriptionPositions := utils.FuzzyMatch(query, manifest.Description)
+
+			result.namePositions = namePositions
+			result.categoryPositions = categoryPositions
+			result.descriptionPositions = descriptionPositions
+			result.score = maxInt(nameScore*3, categoryScore*2, descriptionScore)
+			if result.score == 0 {
+				continue
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].name < results[j].name
+	})
+
+	return results
+}
+
+// truncate shortens the text to maxLength characters and drops the positions beyond it
+func truncate(text string, positions []int, maxLength int) (string, []int) {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text, positions
+	}
+
+	kept := []int{}
+	for _, position := range positions {
+		if position < maxLength-3 {
+			kept = append(kept, position)
+		}
+	}
+
+	return string(runes[:maxLength-3]) + "...", kept
+}
+
+// printSearchTable prints the table like tabwriter does in other commands. It can't
+// use tabwriter because the highlight escape codes would be counted as column width.
+func printSearchTable(headers []string, rows [][]searchCell) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = len(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = maxInt(widths[i], len([]rune(cell.text)))
+		}
+	}
+
+	for i, header := range headers {
+		fmt.Print(pad(header, len(header), widths[i], i == len(headers)-1))
+	}
+	fmt.Println()
+
+	colored := canHighlight()
+	for _, row := range rows {
+		for i, cell := range row {
+			text := cell.text
+			if colored {
+				text = highlight(cell.text, cell.positions)
+			}
+			fmt.Print(pad(text, len([]rune(cell.text)), widths[i], i == len(row)-1))
+		}
+		fmt.Println()
+	}
+}
+
+// pad appends spaces to text (whose visible length is length) to fill the column
+func pad(text string, length, width int, isLastColumn bool) string {
+	if isLastColumn {
+		return text
+	}
+
+	// same as tabwriter in other commands: minimum width of 15 and padding of 1
+	columnWidth := maxInt(15, width+1)
+	return text + strings.Repeat(" ", columnWidth-length)
+}
+
+// highlight wraps the characters at the given positions with bold yellow escape codes
+func highlight(text string, positions []int) string {
+	if len(positions) == 0 {
+		return text
+	}
+
+	matched := make(map[int]bool)
+	for _, position := range positions {
+		matched[position] = true
+	}
+
+	var sb strings.Builder
+	for i, r := range []rune(text) {
+		if matched[i] {
+			sb.WriteString("\033[1;33m")
+			sb.WriteRune(r)
+			sb.WriteString("\033[0m")
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// canHighlight returns true if stdout is a terminal that understands escape codes
+func canHighlight() bool {
+	if runtime.GOOS == "windows" {
+		return false
+	}
+
+	return isTerminal(os.Stdout)
+}
+
+func maxInt(values ...int) int {
+	max := values[0]
+	for _, value := range values[1:] {
+		if value > max {
+			max = value
+		}
+	}
+	return max
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().StringVarP(&SearchCategory, "category", "c", "", "only show apps from this category")
+	searchCmd.Flags().BoolVar(&SearchHasPlans, "has-plans", false, "only show apps that have plans")
+}
@@ -25,7 +25,7 @@ import (
 // showCmd represents the show command
 var showCmd = &cobra.Command{
 	Use:     "show",
-	Example: "kubemart show APP_NAME",
+	Example: "kubemart show APP_NAME\nkubemart show SOURCE/APP_NAME\nkubemart show INSTANCE_NAME",
 	Short:   "Show the application's post-install message",
 	Args:    cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -50,9 +50,15 @@ func (cs *Clientset) RunShow(args []string) error {
 	}
 
 	// check if app exists in cluster
-	_, err := cs.GetApp(appName)
+	catalogName, name := utils.ParseAppRef(appName)
+	app, err := cs.GetApp(name)
 	if err != nil {
-		return fmt.Errorf("%s app is not installed in this cluster", appName)
+		return fmt.Errorf("%s app is not installed in this cluster", name)
+	}
+
+	// the App may be an instance of the app with another name
+	if catalogName == "" {
+		appName = appCatalogName(app)
 	}
 
 	appPostInstall, err := utils.GetPostInstallMarkdown(appName)
@@ -17,22 +17,43 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/forestgiant/sliceutil"
+	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
 	"github.com/spf13/cobra"
 )
 
+// UninstallCascade is used to uninstall the apps depending on the given apps too
+var UninstallCascade bool
+
+// UninstallRemoveOrphans is used to uninstall the dependencies no longer needed too
+var UninstallRemoveOrphans bool
+
 // uninstallCmd represents the uninstall command
 var uninstallCmd = &cobra.Command{
 	Use:     "uninstall APP_NAME",
-	Example: "kubemart uninstall rabbitmq\nkubemart uninstall wordpress,jenkins",
+	Example: "kubemart uninstall rabbitmq\nkubemart uninstall wordpress,jenkins\nkubemart uninstall mariadb --cascade\nkubemart uninstall wordpress --remove-orphans",
 	Short:   "Uninstall application(s)",
-	Args:    cobra.MinimumNArgs(1),
+	Long: `This command will uninstall the application(s) from the Kubernetes cluster.
+
+An app other installed apps depend on can't be uninstalled. Use '--cascade' to
+uninstall these apps first, and '--remove-orphans' to also uninstall the dependencies
+of the uninstalled apps that no other installed app needs.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		err := validateDryRunAndOutputFlags()
+		if err != nil {
+			return err
+		}
+
 		cs, err := NewClientFromLocalKubeConfig()
 		if err != nil {
 			return err
 		}
+		cs.DryRun = DryRun
 
 		err = cs.RunUninstall(args)
 		if err != nil {
@@ -43,28 +64,214 @@ var uninstallCmd = &cobra.Command{
 	},
 }
 
+// RunUninstall deletes the apps, after checking no other installed app depends on them
 func (cs *Clientset) RunUninstall(args []string) error {
-	apps := strings.Split(args[0], ",")
+	requestedApps := strings.Split(args[0], ",")
+	apps, err := cs.ResolveUninstallOrder(requestedApps)
+	if err != nil {
+		return err
+	}
+
 	deletedApps := []string{}
+	deletedObjects := []*operator.App{}
 
 	for _, app := range apps {
-		err := cs.DeleteApp(app)
+		object, err := cs.deleteApp(app)
 		if err != nil {
 			return fmt.Errorf("unable to delete %s app - %v", app, err)
 		}
 
 		deletedApps = append(deletedApps, app)
+		deletedObjects = append(deletedObjects, object)
 	}
 
 	if len(deletedApps) > 0 {
-		fmt.Printf("App(s) now scheduled for deletion: %s\n", strings.Join(deletedApps, ", "))
+		fmt.Fprintf(messages(), "App(s) now scheduled for deletion%s: %s\n", dryRunSuffix(), strings.Join(deletedApps, ", "))
+	}
+
+	err = printApps(deletedObjects)
+	if err != nil {
+		return err
+	}
+
+	if Wait && dryRunSuffix() == "" {
+		return cs.WaitForApps(deletedApps, WaitTimeout, uninstallCheck)
 	}
 
 	return nil
 }
 
+// ResolveUninstallOrder checks the apps can be uninstalled i.e. no other installed app
+// depends on them, and returns them dependents first. With '--cascade', the dependents
+// are uninstalled too, and with '--remove-orphans' the dependencies no longer needed.
+func (cs *Clientset) ResolveUninstallOrder(requestedApps []string) ([]string, error) {
+	installedApps, err := cs.ListApps()
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make(map[string]string)
+	dependencies := make(map[string][]string)
+	for i := range installedApps.Items {
+		app := &installedApps.Items[i]
+		catalogName := appCatalogName(app)
+		installed[app.Name] = catalogName
+
+		if _, found := dependencies[catalogName]; found {
+			continue
+		}
+
+		dependencies[catalogName] = []string{}
+		manifest, err := utils.GetAppManifest(catalogName)
+		if err != nil {
+			utils.DebugPrintf("Unable to check the dependencies of %s app - %v\n", catalogName, err)
+			continue
+		}
+
+		for _, dependency := range manifest.Dependencies {
+			name, _ := utils.ParseDependency(dependency)
+			dependencies[catalogName] = append(dependencies[catalogName], name)
+		}
+	}
+
+	apps, err := planUninstall(installed, dependencies, requestedApps, UninstallCascade, UninstallRemoveOrphans)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(apps) > len(requestedApps) {
+		fmt.Fprintln(messages(), "The following apps will be uninstalled, in this order:")
+		for _, app := range apps {
+			fmt.Fprintf(messages(), "  %s\n", app)
+		}
+	}
+
+	return apps, nil
+}
+
+// planUninstall returns the apps to uninstall, dependents first. The installed apps map
+// the App names to their app in the catalog, and dependencies map the apps of the catalog
+// to the apps they depend on.
+func planUninstall(installed map[string]string, dependencies map[string][]string, requestedApps []string, cascade, removeOrphans bool) ([]string, error) {
+	installedNames := []string{}
+	for name := range installed {
+		installedNames = append(installedNames, name)
+	}
+	sort.Strings(installedNames)
+
+	removing := make(map[string]bool)
+	for _, app := range requestedApps {
+		if _, found := installed[app]; !found {
+			return nil, fmt.Errorf("%s app is not installed in this cluster", app)
+		}
+		removing[app] = true
+	}
+
+	// dependsOn returns true if the app needs the other app, which is the case
+	// unless another instance of the same app stays installed
+	dependsOn := func(app, other string) bool {
+		if !sliceutil.Contains(dependencies[installed[app]], installed[other]) {
+			return false
+		}
+
+		for name, catalogName := range installed {
+			if name != other && catalogName == installed[other] && !removing[name] {
+				return false
+			}
+		}
+		return true
+	}
+
+	dependentsOf := func(app string) []string {
+		dependents := []string{}
+		for _, name := range installedNames {
+			if name != app && !removing[name] && dependsOn(name, app) {
+				dependents = append(dependents, name)
+			}
+		}
+		return dependents
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, app := range sortedKeys(removing) {
+			dependents := dependentsOf(app)
+			if len(dependents) == 0 {
+				continue
+			}
+
+			if !cascade {
+				return nil, fmt.Errorf("%s app can't be uninstalled because %s depend(s) on it - use '--cascade' to uninstall them too", app, strings.Join(dependents, ", "))
+			}
+
+			for _, dependent := range dependents {
+				removing[dependent] = true
+			}
+			changed = true
+		}
+	}
+
+	for changed := removeOrphans; changed; {
+		changed = false
+		for _, app := range installedNames {
+			if removing[app] || len(dependentsOf(app)) > 0 {
+				continue
+			}
+
+			// only the dependencies of the uninstalled apps are removed
+			for _, removed := range sortedKeys(removing) {
+				if sliceutil.Contains(dependencies[installed[removed]], installed[app]) {
+					removing[app] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	// dependents first
+	ordered := []string{}
+	visited := make(map[string]bool)
+	var visit func(app string)
+	visit = func(app string) {
+		if visited[app] {
+			return
+		}
+		visited[app] = true
+
+		for _, name := range sortedKeys(removing) {
+			if name != app && sliceutil.Contains(dependencies[installed[name]], installed[app]) {
+				visit(name)
+			}
+		}
+		ordered = append(ordered, app)
+	}
+
+	for _, app := range requestedApps {
+		visit(app)
+	}
+	for _, app := range sortedKeys(removing) {
+		visit(app)
+	}
+
+	return ordered, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func init() {
 	rootCmd.AddCommand(uninstallCmd)
+	uninstallCmd.Flags().BoolVar(&UninstallCascade, "cascade", false, "also uninstall the apps depending on the given apps, first")
+	uninstallCmd.Flags().BoolVar(&UninstallRemoveOrphans, "remove-orphans", false, "also uninstall the dependencies no other installed app needs")
+	addDryRunAndOutputFlags(uninstallCmd)
+	addWaitFlags(uninstallCmd)
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+var uninstallTestApps = map[string]string{
+	"wordpress": "wordpress",
+	"mariadb":   "mariadb",
+	"longhorn":  "longhorn",
+	"linkerd":   "linkerd",
+}
+
+var uninstallTestDependencies = map[string][]string{
+	"wordpress": {"mariadb"},
+	"mariadb":   {"longhorn"},
+}
+
+func TestPlanUninstallProtectsDependencies(t *testing.T) {
+	_, err := planUninstall(uninstallTestApps, uninstallTestDependencies, []string{"mariadb"}, false, false)
+	if err == nil || !strings.Contains(err.Error(), "wordpress depend(s) on it") {
+		t.Errorf("Expected mariadb app to be protected by wordpress app but got %v", err)
+	}
+
+	apps, err := planUninstall(uninstallTestApps, uninstallTestDependencies, []string{"wordpress"}, false, false)
+	if err != nil || strings.Join(apps, ",") != "wordpress" {
+		t.Errorf("Expected wordpress app alone but got %v (%v)", apps, err)
+	}
+}
+
+func TestPlanUninstallCascade(t *testing.T) {
+	apps, err := planUninstall(uninstallTestApps, uninstallTestDependencies, []string{"longhorn"}, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(apps, ",") != "wordpress,mariadb,longhorn" {
+		t.Errorf("Expected dependents first but got %v", apps)
+	}
+}
+
+func TestPlanUninstallRemoveOrphans(t *testing.T) {
+	apps, err := planUninstall(uninstallTestApps, uninstallTestDependencies, []string{"wordpress"}, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(apps, ",") != "wordpress,mariadb,longhorn" {
+		t.Errorf("Expected the dependencies of wordpress app to be removed but got %v", apps)
+	}
+}
+
+func TestPlanUninstallOtherInstance(t *testing.T) {
+	installed := map[string]string{"wordpress": "wordpress", "mariadb": "mariadb", "db-b": "mariadb", "longhorn": "longhorn"}
+
+	apps, err := planUninstall(installed, uninstallTestDependencies, []string{"mariadb"}, false, false)
+	if err != nil || strings.Join(apps, ",") != "mariadb" {
+		t.Errorf("Expected mariadb app to be removable while db-b instance stays but got %v (%v)", apps, err)
+	}
+}
@@ -17,28 +17,75 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/forestgiant/sliceutil"
 	utils "github.com/kubemart/kubemart-cli/pkg/utils"
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
 	"github.com/spf13/cobra"
 )
 
+// UpdateAll is used to update every app with a new version
+var UpdateAll bool
+
+// UpdateCategory is used to only update the apps from this category
+var UpdateCategory string
+
 // updateCmd represents the update command
 var updateCmd = &cobra.Command{
 	Use:     "update APP_NAME",
-	Example: "kubemart update rabbitmq",
-	Short:   "Update an application",
-	Args:    cobra.MinimumNArgs(1),
+	Example: "kubemart update rabbitmq\nkubemart update wordpress,mariadb\nkubemart update --all\nkubemart update --category database -y",
+	Short:   "Update application(s)",
+	Long: `This command will update the application(s) to their new version.
+
+With several apps, '--all' or '--category', the apps with a new version are shown and
+updated after confirmation (see '--yes'), dependencies first. The apps without a new
+version and the terminating ones are skipped.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if UpdateAll || UpdateCategory != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if UpdateAll || UpdateCategory != "" || (len(args) > 0 && strings.Contains(args[0], ",")) {
+			err := validateDryRunAndOutputFlags()
+			if err != nil {
+				return err
+			}
+
+			cs, err := NewClientFromLocalKubeConfig()
+			if err != nil {
+				return err
+			}
+			cs.DryRun = DryRun
+
+			appNames := []string{}
+			if len(args) > 0 && !UpdateAll {
+				appNames = strings.Split(args[0], ",")
+			}
+			return cs.RunBulkUpdate(appNames, UpdateCategory)
+		}
+
 		appName := args[0]
 		if appName == "" {
 			return fmt.Errorf("please provide an app name")
 		}
 		utils.DebugPrintf("App name to update: %s\n", appName)
 
+		err := validateDryRunAndOutputFlags()
+		if err != nil {
+			return err
+		}
+
 		cs, err := NewClientFromLocalKubeConfig()
 		if err != nil {
 			return err
 		}
+		cs.DryRun = DryRun
 
 		err = cs.RunUpdate(&appName)
 		if err != nil {
@@ -50,17 +97,185 @@ var updateCmd = &cobra.Command{
 }
 
 func (cs *Clientset) RunUpdate(appName *string) error {
-	err := cs.UpdateApp(*appName)
+	app, err := cs.updateApp(*appName)
 	if err != nil {
 		return fmt.Errorf("unable to update app - %v", err)
 	}
 
-	fmt.Printf("%s app is now scheduled to be updated\n", *appName)
+	fmt.Fprintf(messages(), "%s app is now scheduled to be updated%s\n", *appName, dryRunSuffix())
+	err = printApps([]*operator.App{app})
+	if err != nil {
+		return err
+	}
+
+	if Wait && dryRunSuffix() == "" {
+		return cs.WaitForApps([]string{*appName}, WaitTimeout, updateCheck(app.Status.NewUpdateVersion, app.Status.LastStatus))
+	}
+
 	return nil
 }
 
+// RunBulkUpdate updates the given apps (all apps when empty) of the category (any
+// category when empty) that have a new version, dependencies first
+func (cs *Clientset) RunBulkUpdate(appNames []string, category string) error {
+	installedApps, err := cs.ListApps()
+	if err != nil {
+		return err
+	}
+
+	installed := make(map[string]operator.App)
+	for _, app := range installedApps.Items {
+		installed[app.Name] = app
+	}
+
+	for _, appName := range appNames {
+		if _, found := installed[appName]; !found {
+			return fmt.Errorf("%s app is not installed in this cluster", appName)
+		}
+	}
+
+	candidates := []operator.App{}
+	dependencies := make(map[string][]string)
+	for _, app := range installedApps.Items {
+		if len(appNames) > 0 && !sliceutil.Contains(appNames, app.Name) {
+			continue
+		}
+
+		manifest, err := utils.GetAppManifest(appCatalogName(&app))
+		if err != nil {
+			utils.DebugPrintf("Unable to load %s app manifest - %v\n", app.Name, err)
+		}
+
+		if category != "" && !strings.EqualFold(manifest.Category, category) {
+			continue
+		}
+
+		if !app.DeletionTimestamp.IsZero() {
+			fmt.Fprintf(messages(), "Skipping %s app, it is being deleted\n", app.Name)
+			continue
+		}
+
+		if !app.Status.NewUpdateAvailable {
+			if len(appNames) > 0 {
+				fmt.Fprintf(messages(), "Skipping %s app, it is up to date\n", app.Name)
+			}
+			continue
+		}
+
+		candidates = append(candidates, app)
+		for _, dependency := range manifest.Dependencies {
+			name, _ := utils.ParseDependency(dependency)
+			dependencies[app.Name] = append(dependencies[app.Name], name)
+		}
+	}
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(messages(), "All apps are up to date")
+		return nil
+	}
+
+	apps := orderUpdates(candidates, dependencies)
+
+	w := tabwriter.NewWriter(messages(), 15, 0, 1, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "NAME\tCURRENT VERSION\tNEW VERSION")
+	for _, app := range apps {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", app.Name, app.Status.InstalledVersion, app.Status.NewUpdateVersion)
+	}
+	w.Flush()
+
+	if !proceedWithoutPrompt {
+		proceed, err := confirm(promptInput, os.Stdout, fmt.Sprintf("Update %d app(s)?", len(apps)))
+		if err != nil {
+			return fmt.Errorf("%v - use '--yes' to update without confirmation", err)
+		}
+
+		if !proceed {
+			return fmt.Errorf("operation cancelled")
+		}
+	}
+
+	updatedApps := []string{}
+	updatedObjects := []*operator.App{}
+	failures := []string{}
+	for _, app := range apps {
+		object, err := cs.updateApp(app.Name)
+		if err != nil {
+			fmt.Fprintf(messages(), "Unable to update %s app - %v\n", app.Name, err)
+			failures = append(failures, app.Name)
+			continue
+		}
+
+		updatedApps = append(updatedApps, app.Name)
+		updatedObjects = append(updatedObjects, object)
+
+		// the dependents are only updated once their dependencies are
+		if Wait && dryRunSuffix() == "" {
+			err = cs.WaitForApps([]string{app.Name}, WaitTimeout, updateCheck(app.Status.NewUpdateVersion, app.Status.LastStatus))
+			if err != nil {
+				fmt.Fprintf(messages(), "%v\n", err)
+				failures = append(failures, app.Name)
+			}
+		}
+	}
+
+	if len(updatedApps) > 0 {
+		fmt.Fprintf(messages(), "App(s) now scheduled to be updated%s: %s\n", dryRunSuffix(), strings.Join(updatedApps, ", "))
+	}
+
+	err = printApps(updatedObjects)
+	if err != nil {
+		return err
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	err = fmt.Errorf("unable to update %s app(s)", strings.Join(failures, ", "))
+	if len(failures) < len(apps) {
+		return &exitError{code: ExitCodePartialFailure, err: err}
+	}
+	return err
+}
+
+// orderUpdates sorts the apps by name, then moves the dependencies before their dependents.
+// Dependencies map the App names to the apps of the catalog they depend on.
+func orderUpdates(apps []operator.App, dependencies map[string][]string) []operator.App {
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+
+	ordered := []operator.App{}
+	visited := make(map[string]bool)
+	var visit func(app operator.App)
+	visit = func(app operator.App) {
+		if visited[app.Name] {
+			return
+		}
+		visited[app.Name] = true
+
+		for _, other := range apps {
+			if sliceutil.Contains(dependencies[app.Name], appCatalogName(&other)) {
+				visit(other)
+			}
+		}
+		ordered = append(ordered, app)
+	}
+
+	for _, app := range apps {
+		visit(app)
+	}
+
+	return ordered
+}
+
 func init() {
 	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVar(&UpdateAll, "all", false, "update every app with a new version")
+	updateCmd.Flags().StringVarP(&UpdateCategory, "category", "c", "", "only update the apps from this category")
+	updateCmd.Flags().BoolVarP(&proceedWithoutPrompt, "yes", "y", false, "skip interactive y/n prompt by answering 'y'")
+	addDryRunAndOutputFlags(updateCmd)
+	addWaitFlags(updateCmd)
 
 	// Here you will define your flags and configuration settings.
 
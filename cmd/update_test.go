@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+)
+
+func TestOrderUpdates(t *testing.T) {
+	apps := []operator.App{*NewApp("wordpress", ""), *NewApp("linkerd", ""), *NewApp("mariadb", ""), *NewApp("longhorn", "")}
+	dependencies := map[string][]string{
+		"wordpress": {"mariadb"},
+		"mariadb":   {"longhorn"},
+	}
+
+	names := []string{}
+	for _, app := range orderUpdates(apps, dependencies) {
+		names = append(names, app.Name)
+	}
+
+	if strings.Join(names, ",") != "linkerd,longhorn,mariadb,wordpress" {
+		t.Errorf("Expected dependencies first but got %v", names)
+	}
+}
@@ -85,6 +85,10 @@ var (
 				}
 				fmt.Printf("ServiceAccount (kubemart-daemon-svc-acc) status: %s\n", saStatus)
 
+				if utils.IsOfflineMode() {
+					return
+				}
+
 				res, err := latest.Check(githubTag, strings.Replace(VersionCli, "v", "", 1))
 				if err != nil {
 					fmt.Printf("Checking for a newer version failed with %s\n", err)
@@ -99,6 +103,10 @@ var (
 			default:
 				fmt.Printf("v%s\n", VersionCli)
 
+				if utils.IsOfflineMode() {
+					return
+				}
+
 				res, err := latest.Check(githubTag, strings.Replace(VersionCli, "v", "", 1))
 				if err != nil {
 					fmt.Printf("Checking for a newer version failed with %s\n", err)
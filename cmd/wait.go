@@ -0,0 +1,308 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	operator "github.com/kubemart/kubemart-operator/api/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Wait is used to wait until the operator is done with the apps
+var Wait bool
+
+// WaitTimeout is how long to wait for when '--wait' flag is used
+var WaitTimeout time.Duration
+
+// appWatchEvent is an event of the watch stream of App resources
+type appWatchEvent struct {
+	Type   watch.EventType `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// appCheck tells if the operator is done with the app, or returns an error if it failed
+type appCheck func(eventType watch.EventType, app *operator.App) (bool, error)
+
+// addWaitFlags adds '--wait' and '--timeout' flags to the command
+func addWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&Wait, "wait", false, "wait until the operator is done with the app(s) and show the progress")
+	cmd.Flags().DurationVar(&WaitTimeout, "timeout", 10*time.Minute, "how long to wait for when '--wait' flag is used")
+}
+
+// LastStatus values set by the operator on the App
+const (
+	StatusInstallationStarted    = "installation_started"
+	StatusInstallationFinished   = "installation_finished"
+	StatusInstallationFailed     = "installation_failed"
+	StatusUpdateStarted          = "update_started"
+	StatusUpdateFinished         = "update_finished"
+	StatusUpdateFailed           = "update_failed"
+	StatusUninstallationStarted  = "uninstallation_started"
+	StatusUninstallationFinished = "uninstallation_finished"
+	StatusUninstallationFailed   = "uninstallation_failed"
+)
+
+// isFinishedStatus returns true if the operator reports the end of an install or an update
+func isFinishedStatus(status string) bool {
+	switch status {
+	case StatusInstallationFinished, StatusUpdateFinished:
+		return true
+	}
+	return false
+}
+
+// installCheck is done when the app is installed
+func installCheck(eventType watch.EventType, app *operator.App) (bool, error) {
+	if eventType == watch.Deleted {
+		return false, fmt.Errorf("%s app was deleted", app.Name)
+	}
+
+	if app.Status.LastStatus == StatusInstallationFailed {
+		return false, fmt.Errorf("%s app failed - %s", app.Name, app.Status.LastStatus)
+	}
+
+	return isFinishedStatus(app.Status.LastStatus), nil
+}
+
+// updateCheck returns an appCheck that is done when the app runs the given version.
+// When the version is unknown, it's done when the status changes to a finished one.
+// The status before the update may be a failure of a previous attempt, so a failure
+// only counts once the status changed.
+func updateCheck(version, statusBeforeUpdate string) appCheck {
+	changed := false
+	return func(eventType watch.EventType, app *operator.App) (bool, error) {
+		if eventType == watch.Deleted {
+			return false, fmt.Errorf("%s app was deleted", app.Name)
+		}
+
+		if app.Status.LastStatus != statusBeforeUpdate {
+			changed = true
+		}
+
+		if changed && app.Status.LastStatus == StatusUpdateFailed {
+			return false, fmt.Errorf("%s app failed - %s", app.Name, app.Status.LastStatus)
+		}
+
+		if !isFinishedStatus(app.Status.LastStatus) {
+			return false, nil
+		}
+
+		if version != "" {
+			return app.Status.InstalledVersion == version, nil
+		}
+		return changed, nil
+	}
+}
+
+// planCheck returns an appCheck that is done when the operator finished applying the plan.
+// The status may already be update_finished before the plan changed, so only a status
+// written after the change i.e. at another resource version than the patched App counts.
+func planCheck(plan, patchedResourceVersion string) appCheck {
+	return func(eventType watch.EventType, app *operator.App) (bool, error) {
+		if eventType == watch.Deleted {
+			return false, fmt.Errorf("%s app was deleted", app.Name)
+		}
+
+		if app.Spec.Plan != plan {
+			return false, fmt.Errorf("%s app was moved to %s plan meanwhile", app.Name, app.Spec.Plan)
+		}
+
+		if app.ResourceVersion == patchedResourceVersion {
+			return false, nil
+		}
+
+		if app.Status.LastStatus == StatusUpdateFailed {
+			return false, fmt.Errorf("%s app failed - %s", app.Name, app.Status.LastStatus)
+		}
+
+		return isFinishedStatus(app.Status.LastStatus), nil
+	}
+}
+
+// uninstallCheck is done when the app is gone. Only a failed uninstall is an error,
+// the app may have failed to install or update before.
+func uninstallCheck(eventType watch.EventType, app *operator.App) (bool, error) {
+	if app.Status.LastStatus == StatusUninstallationFailed {
+		return false, fmt.Errorf("%s app failed - %s", app.Name, app.Status.LastStatus)
+	}
+
+	return eventType == watch.Deleted, nil
+}
+
+// WaitForApps watches the apps and prints their status changes until check
+// says all of them are done. It returns an error on failure or timeout.
+func (cs *Clientset) WaitForApps(appNames []string, timeout time.Duration, check appCheck) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	pending := make(map[string]bool)
+	for _, appName := range appNames {
+		pending[appName] = true
+	}
+	lastStatuses := make(map[string]string)
+
+	fmt.Fprintf(messages(), "Waiting for %s app(s)...\n", strings.Join(appNames, ", "))
+	resourceVersion := ""
+	for len(pending) > 0 {
+		remaining := time.Until(deadlineOf(ctx))
+		if remaining <= 0 {
+			return fmt.Errorf("timed out after %s waiting for %s app(s)", timeout, strings.Join(pendingNames(appNames, pending), ", "))
+		}
+
+		// start from the current state, the watch then streams what happens next
+		if resourceVersion == "" {
+			apps, err := cs.ListApps()
+			if err != nil {
+				return err
+			}
+			resourceVersion = apps.ResourceVersion
+
+			found := make(map[string]bool)
+			for i := range apps.Items {
+				found[apps.Items[i].Name] = true
+				err = handleAppEvent(watch.Added, &apps.Items[i], pending, lastStatuses, check)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, appName := range pendingNames(appNames, pending) {
+				if !found[appName] {
+					app := &operator.App{ObjectMeta: metav1.ObjectMeta{Name: appName}}
+					err = handleAppEvent(watch.Deleted, app, pending, lastStatuses, check)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		stream, err := cs.RESTClient().
+			Get().
+			AbsPath(baseURL).
+			Param("watch", "true").
+			Param("resourceVersion", resourceVersion).
+			Param("timeoutSeconds", fmt.Sprintf("%d", int(remaining.Seconds())+1)).
+			Stream(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return fmt.Errorf("unable to watch apps - %v", err)
+		}
+
+		resourceVersion, err = readAppEvents(stream, resourceVersion, pending, lastStatuses, check)
+		stream.Close()
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(messages(), "Done: %s\n", strings.Join(appNames, ", "))
+	return nil
+}
+
+// readAppEvents reads the watch stream until it ends, and returns the last resource version.
+// It's empty when the watch has to start over.
+func readAppEvents(stream io.Reader, resourceVersion string, pending map[string]bool, lastStatuses map[string]string, check appCheck) (string, error) {
+	decoder := json.NewDecoder(stream)
+	for len(pending) > 0 {
+		event := appWatchEvent{}
+		err := decoder.Decode(&event)
+		if err == io.EOF {
+			return resourceVersion, nil
+		}
+		if err != nil {
+			return resourceVersion, fmt.Errorf("unable to read app events - %v", err)
+		}
+
+		if event.Type == watch.Error {
+			status := metav1.Status{}
+			_ = json.Unmarshal(event.Object, &status)
+			if status.Code == http.StatusGone {
+				// the resource version is too old, start over
+				return "", nil
+			}
+			return resourceVersion, fmt.Errorf("unable to watch apps - %s", status.Message)
+		}
+
+		app := &operator.App{}
+		err = json.Unmarshal(event.Object, app)
+		if err != nil {
+			return resourceVersion, fmt.Errorf("unable to parse app event - %v", err)
+		}
+		resourceVersion = app.ResourceVersion
+
+		err = handleAppEvent(event.Type, app, pending, lastStatuses, check)
+		if err != nil {
+			return resourceVersion, err
+		}
+	}
+
+	return resourceVersion, nil
+}
+
+// handleAppEvent prints the status of the app when it changed, and removes
+// the app from pending ones when check says it's done
+func handleAppEvent(eventType watch.EventType, app *operator.App, pending map[string]bool, lastStatuses map[string]string, check appCheck) error {
+	if !pending[app.Name] {
+		return nil
+	}
+
+	if app.Status.LastStatus != lastStatuses[app.Name] {
+		lastStatuses[app.Name] = app.Status.LastStatus
+		fmt.Fprintf(messages(), "%s: %s\n", app.Name, app.Status.LastStatus)
+	}
+	if eventType == watch.Deleted {
+		fmt.Fprintf(messages(), "%s: deleted\n", app.Name)
+	}
+
+	done, err := check(eventType, app)
+	if err != nil {
+		return err
+	}
+	if done {
+		delete(pending, app.Name)
+	}
+
+	return nil
+}
+
+func deadlineOf(ctx context.Context) time.Time {
+	deadline, _ := ctx.Deadline()
+	return deadline
+}
+
+// pendingNames returns the app names that are still pending, in the original order
+func pendingNames(appNames []string, pending map[string]bool) []string {
+	names := []string{}
+	for _, appName := range appNames {
+		if pending[appName] {
+			names = append(names, appName)
+		}
+	}
+	return names
+}
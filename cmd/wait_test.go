@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadAppEventsInstall(t *testing.T) {
+	stream := strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"2"},"status":{"lastStatus":"installation_started"}}}
+{"type":"MODIFIED","object":{"metadata":{"name":"linkerd","resourceVersion":"3"},"status":{"lastStatus":"installation_started"}}}
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"4"},"status":{"lastStatus":"installation_finished"}}}
+`)
+
+	pending := map[string]bool{"rabbitmq": true}
+	resourceVersion, err := readAppEvents(stream, "1", pending, map[string]string{}, installCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 0 || resourceVersion != "4" {
+		t.Errorf("Expected rabbitmq to be done at resource version 4 but got %v at %s", pending, resourceVersion)
+	}
+}
+
+func TestReadAppEventsFailed(t *testing.T) {
+	stream := strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"2"},"status":{"lastStatus":"installation_failed"}}}
+`)
+
+	pending := map[string]bool{"rabbitmq": true}
+	_, err := readAppEvents(stream, "1", pending, map[string]string{}, installCheck)
+	if err == nil || !strings.Contains(err.Error(), "installation_failed") {
+		t.Errorf("Expected failed status to be an error but got %v", err)
+	}
+}
+
+func TestReadAppEventsUninstall(t *testing.T) {
+	stream := strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"2"},"status":{"lastStatus":"uninstallation_started"}}}
+{"type":"DELETED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"3"}}}
+`)
+
+	pending := map[string]bool{"rabbitmq": true}
+	_, err := readAppEvents(stream, "1", pending, map[string]string{}, uninstallCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 0 {
+		t.Errorf("Expected rabbitmq to be deleted but got %v", pending)
+	}
+}
+
+func TestReadAppEventsUninstallAfterFailedInstall(t *testing.T) {
+	// e.g. 'uninstall --wait' of an app that failed to install, or '--reinstall' of it
+	stream := strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"2"},"status":{"lastStatus":"installation_failed"}}}
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"3"},"status":{"lastStatus":"uninstallation_started"}}}
+{"type":"DELETED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"4"}}}
+`)
+
+	pending := map[string]bool{"rabbitmq": true}
+	_, err := readAppEvents(stream, "1", pending, map[string]string{}, uninstallCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 0 {
+		t.Errorf("Expected rabbitmq to be deleted but got %v", pending)
+	}
+}
+
+func TestReadAppEventsUninstallFailed(t *testing.T) {
+	stream := strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"2"},"status":{"lastStatus":"uninstallation_failed"}}}
+`)
+
+	_, err := readAppEvents(stream, "1", map[string]bool{"rabbitmq": true}, map[string]string{}, uninstallCheck)
+	if err == nil || !strings.Contains(err.Error(), "uninstallation_failed") {
+		t.Errorf("Expected failed uninstall to be an error but got %v", err)
+	}
+}
+
+func TestReadAppEventsUpdateAfterFailedUpdate(t *testing.T) {
+	// the update is retried, the status is still the one of the previous attempt
+	stream := strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"2"},"status":{"lastStatus":"update_failed","installedVersion":"3.8.8"}}}
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"3"},"status":{"lastStatus":"update_started","installedVersion":"3.8.8"}}}
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"4"},"status":{"lastStatus":"update_finished","installedVersion":"3.8.9"}}}
+`)
+
+	pending := map[string]bool{"rabbitmq": true}
+	_, err := readAppEvents(stream, "1", pending, map[string]string{}, updateCheck("3.8.9", StatusUpdateFailed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 0 {
+		t.Errorf("Expected rabbitmq to be updated but got %v", pending)
+	}
+}
+
+func TestReadAppEventsUpdateFailedAgain(t *testing.T) {
+	stream := strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"2"},"status":{"lastStatus":"update_failed"}}}
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"3"},"status":{"lastStatus":"update_started"}}}
+{"type":"MODIFIED","object":{"metadata":{"name":"rabbitmq","resourceVersion":"4"},"status":{"lastStatus":"update_failed"}}}
+`)
+
+	_, err := readAppEvents(stream, "1", map[string]bool{"rabbitmq": true}, map[string]string{}, updateCheck("3.8.9", StatusUpdateFailed))
+	if err == nil || !strings.Contains(err.Error(), "update_failed") {
+		t.Errorf("Expected the new failed update to be an error but got %v", err)
+	}
+}
+
+func TestReadAppEventsPlanAfterFinishedUpdate(t *testing.T) {
+	// the status is still the one of the previous update until the operator applies the plan
+	stream := strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"mariadb","resourceVersion":"2"},"spec":{"plan":"20Gi"},"status":{"lastStatus":"update_finished"}}}
+{"type":"MODIFIED","object":{"metadata":{"name":"mariadb","resourceVersion":"3"},"spec":{"plan":"20Gi"},"status":{"lastStatus":"update_started"}}}
+`)
+
+	pending := map[string]bool{"mariadb": true}
+	_, err := readAppEvents(stream, "1", pending, map[string]string{}, planCheck("20Gi", "2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 1 {
+		t.Errorf("Expected mariadb to be still pending but got %v", pending)
+	}
+
+	stream = strings.NewReader(`
+{"type":"MODIFIED","object":{"metadata":{"name":"mariadb","resourceVersion":"4"},"spec":{"plan":"20Gi"},"status":{"lastStatus":"update_finished"}}}
+`)
+
+	_, err = readAppEvents(stream, "3", pending, map[string]string{}, planCheck("20Gi", "2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 0 {
+		t.Errorf("Expected mariadb to be resized but got %v", pending)
+	}
+}
+
+func TestReadAppEventsExpired(t *testing.T) {
+	stream := strings.NewReader(`
+{"type":"ERROR","object":{"kind":"Status","code":410,"message":"too old resource version"}}
+`)
+
+	resourceVersion, err := readAppEvents(stream, "1", map[string]bool{"rabbitmq": true}, map[string]string{}, installCheck)
+	if err != nil || resourceVersion != "" {
+		t.Errorf("Expected the watch to start over but got %v and resource version %s", err, resourceVersion)
+	}
+}
+
+func TestIsFinishedStatus(t *testing.T) {
+	statuses := map[string]bool{
+		StatusInstallationStarted:    false,
+		StatusInstallationFinished:   true,
+		StatusInstallationFailed:     false,
+		StatusUpdateStarted:          false,
+		StatusUpdateFinished:         true,
+		StatusUpdateFailed:           false,
+		StatusUninstallationStarted:  false,
+		StatusUninstallationFinished: false,
+		StatusUninstallationFailed:   false,
+		"":                           false,
+		"not_installed":              false,
+	}
+
+	for status, expected := range statuses {
+		if isFinishedStatus(status) != expected {
+			t.Errorf("Expected %q finished to be %t", status, expected)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/forestgiant/sliceutil"
+	"github.com/kubemart/kubemart-cli/pkg/utils"
+)
+
+// NonInteractive disables the prompts of the install command, even on a terminal
+var NonInteractive bool
+
+// promptInput is where the answers to the prompts are read from
+var promptInput = bufio.NewReader(os.Stdin)
+
+// maxPickerApps is the number of search results the app picker shows
+const maxPickerApps = 15
+
+// isInteractive returns true when the user can answer prompts i.e. stdin and stdout
+// are terminals and no machine-readable output was asked for
+func isInteractive() bool {
+	return !NonInteractive && OutputFormat == "" && isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+// isTerminal returns true if the file is a terminal (and not e.g. a pipe)
+func isTerminal(f *os.File) bool {
+	fileInfo, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fileInfo.Mode()&os.ModeCharDevice != 0
+}
+
+// prompt prints the question and returns the trimmed answer
+func prompt(in *bufio.Reader, out io.Writer, question string) (string, error) {
+	fmt.Fprint(out, question)
+	answer, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", fmt.Errorf("unable to read the answer - %v", err)
+	}
+
+	return strings.TrimSpace(answer), nil
+}
+
+// confirm asks a y/n question, anything but y or yes is a no
+func confirm(in *bufio.Reader, out io.Writer, question string) (bool, error) {
+	answer, err := prompt(in, out, fmt.Sprintf("%s y/n: ", question))
+	if err != nil {
+		return false, err
+	}
+
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// pickApp lets the user search the apps and pick one of the results
+func pickApp(in *bufio.Reader, out io.Writer, manifests map[string]utils.AppManifest) (string, error) {
+	query, err := prompt(in, out, "Search apps (leave empty to list all): ")
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		results := searchApps(query, manifests)
+		if len(results) == 0 {
+			query, err = prompt(in, out, "No apps found, search again: ")
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		if len(results) > maxPickerApps {
+			results = results[:maxPickerApps]
+		}
+
+		for i, result := range results {
+			description, _ := truncate(result.manifest.Description, nil, maxDescriptionLength)
+			fmt.Fprintf(out, "  %2d) %s - %s\n", i+1, result.name, description)
+		}
+
+		answer, err := prompt(in, out, fmt.Sprintf("Pick an app [1-%d] or type a new search: ", len(results)))
+		if err != nil {
+			return "", err
+		}
+
+		number, err := strconv.Atoi(answer)
+		if err == nil && number >= 1 && number <= len(results) {
+			return results[number-1].name, nil
+		}
+		query = answer
+	}
+}
+
+// pickPlan shows the plans of the app with their configuration and lets the user
+// pick one by number or label. The plans are shown from the smallest to the largest
+// and the smallest one is picked when the answer is empty.
+func pickPlan(in *bufio.Reader, out io.Writer, appName string, manifest utils.AppManifest) (string, error) {
+	labels := utils.SortAppPlans(manifest)
+
+	fmt.Fprintf(out, "Plans of %s app:\n", appName)
+	for i, label := range labels {
+		configuration := []string{}
+		for _, plan := range manifest.Plans {
+			if plan.Label != label {
+				continue
+			}
+
+			for key, value := range plan.Configuration {
+				configuration = append(configuration, fmt.Sprintf("%s=%s", key, value.Value))
+			}
+		}
+		sort.Strings(configuration)
+
+		line := fmt.Sprintf("  %d) %s", i+1, label)
+		if len(configuration) > 0 {
+			line += fmt.Sprintf(" (%s)", strings.Join(configuration, ", "))
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	for {
+		answer, err := prompt(in, out, fmt.Sprintf("Pick a plan [1-%d] (default 1): ", len(labels)))
+		if err != nil {
+			return "", err
+		}
+
+		if answer == "" {
+			return labels[0], nil
+		}
+
+		number, err := strconv.Atoi(answer)
+		if err == nil && number >= 1 && number <= len(labels) {
+			return labels[number-1], nil
+		}
+
+		if sliceutil.Contains(labels, answer) {
+			return answer, nil
+		}
+
+		fmt.Fprintf(out, "%s is not a plan of %s app\n", answer, appName)
+	}
+}
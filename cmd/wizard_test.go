@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bufio"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func newWizardManifest(t *testing.T, content string) utils.AppManifest {
+	manifest := utils.AppManifest{}
+	err := yaml.Unmarshal([]byte(content), &manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manifest
+}
+
+func TestPickPlan(t *testing.T) {
+	manifest := newWizardManifest(t, `
+plans:
+- label: 5GB
+  configuration:
+    VOLUME_SIZE:
+      value: 5Gi
+- label: 10GB
+  configuration:
+    VOLUME_SIZE:
+      value: 10Gi
+`)
+
+	answers := map[string]string{
+		"\n":            "5GB",
+		"2\n":           "10GB",
+		"10GB\n":        "10GB",
+		"3\nlarge\n1\n": "5GB",
+	}
+
+	for answer, expected := range answers {
+		in := bufio.NewReader(strings.NewReader(answer))
+		plan, err := pickPlan(in, ioutil.Discard, "mariadb", manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if plan != expected {
+			t.Errorf("Expected %s plan for %q answer but got %s", expected, answer, plan)
+		}
+	}
+}
+
+func TestPickPlanNoAnswer(t *testing.T) {
+	manifest := newWizardManifest(t, "plans:\n- label: 5GB\n")
+
+	in := bufio.NewReader(strings.NewReader(""))
+	_, err := pickPlan(in, ioutil.Discard, "mariadb", manifest)
+	if err == nil {
+		t.Error("Expected an error when there is no answer")
+	}
+}
+
+func TestPickApp(t *testing.T) {
+	manifests := map[string]utils.AppManifest{
+		"wordpress": newWizardManifest(t, "category: cms\n"),
+		"mariadb":   newWizardManifest(t, "category: database\n"),
+		"rabbitmq":  newWizardManifest(t, "category: messaging\n"),
+	}
+
+	in := bufio.NewReader(strings.NewReader("xyz\nmaria\n1\n"))
+	var out strings.Builder
+	appName, err := pickApp(in, &out, manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if appName != "mariadb" {
+		t.Errorf("Expected mariadb app but got %s", appName)
+	}
+
+	if !strings.Contains(out.String(), "No apps found") {
+		t.Errorf("Expected no results for the first search but got %q", out.String())
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	answers := map[string]bool{"y\n": true, "Yes\n": true, "n\n": false, "\n": false}
+	for answer, expected := range answers {
+		in := bufio.NewReader(strings.NewReader(answer))
+		proceed, err := confirm(in, ioutil.Discard, "Install?")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if proceed != expected {
+			t.Errorf("Expected %v for %q answer but got %v", expected, answer, proceed)
+		}
+	}
+}
@@ -5,9 +5,10 @@ go 1.16
 require (
 	github.com/charmbracelet/glamour v0.2.0
 	github.com/forestgiant/sliceutil v0.0.0-20160425183142-94783f95db6c
+	github.com/go-git/go-git/v5 v5.4.2
 	github.com/google/go-github v17.0.0+incompatible // indirect
 	github.com/google/go-querystring v1.0.0 // indirect
-	github.com/hashicorp/go-version v1.2.1 // indirect
+	github.com/hashicorp/go-version v1.2.1
 	github.com/kubemart/kubemart-operator v0.0.69
 	github.com/spf13/cobra v1.1.1
 	github.com/stretchr/testify v1.7.0
@@ -18,4 +19,5 @@ require (
 	k8s.io/apiextensions-apiserver v0.18.6
 	k8s.io/apimachinery v0.20.4
 	k8s.io/client-go v0.20.4
+	sigs.k8s.io/yaml v1.2.0
 )
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultAppSetFileName is the app set file used by 'kubemart apply' when '-f' is not given
+const DefaultAppSetFileName = "kubemart.yaml"
+
+// KubemartAppSet is the structure of kubemart.yaml file i.e. the apps a cluster should have
+type KubemartAppSet struct {
+	Apps []AppSetEntry `yaml:"apps"`
+}
+
+// AppSetEntry is an app of the app set
+type AppSetEntry struct {
+	// Name is the app reference i.e. [SOURCE/]APP_NAME
+	Name string `yaml:"name"`
+	// Plan is the plan label, the smallest plan is used when it's empty
+	Plan string `yaml:"plan,omitempty"`
+	// Update is used to update the app when a new version is available
+	Update bool `yaml:"update,omitempty"`
+}
+
+// ReadAppSetFile will load and validate the app set file
+func ReadAppSetFile(appSetFilePath string) (*KubemartAppSet, error) {
+	appSet := &KubemartAppSet{}
+
+	file, err := ioutil.ReadFile(appSetFilePath)
+	if err != nil {
+		return appSet, fmt.Errorf("unable to read %s file - %v", appSetFilePath, err)
+	}
+
+	err = yaml.UnmarshalStrict(file, appSet)
+	if err != nil {
+		return appSet, fmt.Errorf("unable to parse %s file - %v", appSetFilePath, err)
+	}
+
+	seen := make(map[string]bool)
+	for i, entry := range appSet.Apps {
+		if entry.Name == "" {
+			return appSet, fmt.Errorf("app #%d of %s file has no name", i+1, appSetFilePath)
+		}
+
+		_, name := ParseAppRef(entry.Name)
+		if seen[name] {
+			return appSet, fmt.Errorf("%s app is listed more than once in %s file", name, appSetFilePath)
+		}
+		seen[name] = true
+
+		if !IsAppExist(entry.Name) {
+			return appSet, fmt.Errorf("unable to find %s app of %s file", entry.Name, appSetFilePath)
+		}
+
+		planLabels, err := GetAppPlans(entry.Name)
+		if err != nil {
+			return appSet, fmt.Errorf("unable to list %s app's plans - %v", entry.Name, err)
+		}
+
+		if entry.Plan != "" && !containsString(planLabels, entry.Plan) {
+			return appSet, fmt.Errorf("%s plan is not supported for %s app - supported values are %v", entry.Plan, entry.Name, planLabels)
+		}
+	}
+
+	return appSet, nil
+}
+
+// PlanLabelOrSmallest returns the plan label of the entry, or the smallest
+// plan of the app when it's not given. It's empty for apps without plans.
+func (entry AppSetEntry) PlanLabelOrSmallest() (string, error) {
+	if entry.Plan != "" {
+		return entry.Plan, nil
+	}
+
+	planLabels, err := GetAppPlans(entry.Name)
+	if err != nil {
+		return "", fmt.Errorf("unable to list %s app's plans - %v", entry.Name, err)
+	}
+
+	if len(planLabels) == 0 {
+		return "", nil
+	}
+
+	return GetSmallestAppPlan(planLabels), nil
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestReadAppSetFile(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"wordpress/manifest.yaml": "plans:\n- label: 5GB\n  configuration:\n    VOLUME_SIZE:\n      value: 5Gi\n- label: 10GB\n  configuration:\n    VOLUME_SIZE:\n      value: 10Gi\n",
+		"linkerd/manifest.yaml":   "version: 2.10\n",
+	})
+	defer cleanup()
+
+	dir, _ := ioutil.TempDir("", "kubemart-appset")
+	defer os.RemoveAll(dir)
+	appSetFilePath := filepath.Join(dir, DefaultAppSetFileName)
+
+	_ = ioutil.WriteFile(appSetFilePath, []byte("apps:\n- name: wordpress\n- name: linkerd\n  update: true\n"), 0644)
+	appSet, err := ReadAppSetFile(appSetFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []AppSetEntry{{Name: "wordpress"}, {Name: "linkerd", Update: true}}
+	if !elementsMatch(appSet.Apps, expected) {
+		t.Errorf("Expected %+v but actual is %+v", expected, appSet.Apps)
+	}
+
+	planLabel, _ := appSet.Apps[0].PlanLabelOrSmallest()
+	if planLabel != "5GB" {
+		t.Errorf("Expected the smallest plan 5GB but got %s", planLabel)
+	}
+
+	_ = ioutil.WriteFile(appSetFilePath, []byte("apps:\n- name: wordpress\n  plan: 50GB\n"), 0644)
+	_, err = ReadAppSetFile(appSetFilePath)
+	if err == nil {
+		t.Errorf("Expected an error for unknown plan")
+	}
+
+	_ = ioutil.WriteFile(appSetFilePath, []byte("apps:\n- name: wordpress\n- name: wordpress\n"), 0644)
+	_, err = ReadAppSetFile(appSetFilePath)
+	if err == nil {
+		t.Errorf("Expected an error for duplicated app")
+	}
+}
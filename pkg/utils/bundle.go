@@ -0,0 +1,345 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// bundleManifestFileName is the file (at the root of the bundle archive)
+// that describes the catalogs inside the bundle
+const bundleManifestFileName = "bundle.json"
+
+// CatalogBundle is the structure of bundle.json file inside a catalog bundle
+type CatalogBundle struct {
+	CreatedAt         int64                `json:"created_at"`
+	AppsLastUpdatedAt int64                `json:"apps_last_updated_at"`
+	Catalogs          []CatalogBundleEntry `json:"catalogs"`
+}
+
+// CatalogBundleEntry is a catalog inside a catalog bundle
+type CatalogBundleEntry struct {
+	CatalogSource
+	Commit string `json:"commit"`
+}
+
+// IsOfflineMode returns true when catalogs must not be fetched from the network,
+// either because '--offline' flag was used or because it's enabled in ~/.kubemart/config.json
+func IsOfflineMode() bool {
+	if os.Getenv("KUBEMART_OFFLINE") == "true" {
+		return true
+	}
+
+	config, err := ReadConfigFile()
+	if err != nil {
+		return false
+	}
+
+	return config.Offline
+}
+
+// SetOfflineMode will save the offline setting into ~/.kubemart/config.json file
+func SetOfflineMode(offline bool) error {
+	config, err := ReadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	config.Offline = offline
+	return WriteConfigFile(config)
+}
+
+// ExportCatalogBundle will package all registered catalogs (including their Git
+// history) into a .tar.gz file that can be imported on a machine without network access
+func ExportCatalogBundle(bundleFilePath string) (*CatalogBundle, error) {
+	config, err := ReadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &CatalogBundle{
+		CreatedAt:         time.Now().Unix(),
+		AppsLastUpdatedAt: config.AppsLastUpdatedAt,
+	}
+
+	for _, source := range config.Catalogs {
+		catalogDirPath, err := GetCatalogDirectoryPath(source.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		commit, err := GitLatestCommitHash(catalogDirPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s catalog is not cloned properly - %v", source.Name, err)
+		}
+
+		bundle.Catalogs = append(bundle.Catalogs, CatalogBundleEntry{
+			CatalogSource: source,
+			Commit:        commit,
+		})
+	}
+
+	file, err := os.Create(bundleFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s file - %v", bundleFilePath, err)
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifest, err := json.MarshalIndent(bundle, "", " ")
+	if err != nil {
+		return nil, err
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    bundleManifestFileName,
+		Mode:    0644,
+		Size:    int64(len(manifest)),
+		ModTime: time.Unix(bundle.CreatedAt, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tarWriter.Write(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range bundle.Catalogs {
+		catalogDirPath, err := GetCatalogDirectoryPath(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = addDirectoryToTar(tarWriter, catalogDirPath, filepath.Join("catalogs", entry.Name))
+		if err != nil {
+			return nil, fmt.Errorf("unable to package %s catalog - %v", entry.Name, err)
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// addDirectoryToTar will add every file in directory to the archive, under prefix folder
+func addDirectoryToTar(tarWriter *tar.Writer, directory, prefix string) error {
+	return filepath.Walk(directory, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(directory, filePath)
+		if err != nil {
+			return err
+		}
+
+		// links can't be imported safely, see extractTarGz
+		if info.Mode()&os.ModeSymlink != 0 {
+			DebugPrintf("Skipping %s link in the bundle\n", filePath)
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(prefix, relativePath))
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+}
+
+// ImportCatalogBundle will extract a bundle created by ExportCatalogBundle and
+// replace the catalogs it contains. Catalogs that are not in the bundle are kept.
+func ImportCatalogBundle(bundleFilePath string) (*CatalogBundle, error) {
+	bp, err := GetKubemartPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(bp.RootDirectoryPath, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s directory - %v", bp.RootDirectoryPath, err)
+	}
+
+	// extract next to the catalogs, so they can be moved into place with a rename
+	tmpDirPath, err := ioutil.TempDir(bp.RootDirectoryPath, ".import-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary directory - %v", err)
+	}
+	defer os.RemoveAll(tmpDirPath)
+
+	err = extractTarGz(bundleFilePath, tmpDirPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract %s file - %v", bundleFilePath, err)
+	}
+
+	manifest, err := ioutil.ReadFile(filepath.Join(tmpDirPath, bundleManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a catalog bundle - %v", bundleFilePath, err)
+	}
+
+	bundle := &CatalogBundle{}
+	err = json.Unmarshal(manifest, bundle)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s of the bundle - %v", bundleManifestFileName, err)
+	}
+
+	config, err := ReadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	// validate everything before touching the current catalogs
+	for _, entry := range bundle.Catalogs {
+		if !IsValidCatalogName(entry.Name) {
+			return nil, fmt.Errorf("invalid catalog name %q in the bundle", entry.Name)
+		}
+
+		extractedDirPath := filepath.Join(tmpDirPath, "catalogs", entry.Name)
+		commit, err := GitLatestCommitHash(extractedDirPath)
+		if err != nil || commit != entry.Commit {
+			return nil, fmt.Errorf("%s catalog in the bundle is corrupt", entry.Name)
+		}
+	}
+
+	for _, entry := range bundle.Catalogs {
+		extractedDirPath := filepath.Join(tmpDirPath, "catalogs", entry.Name)
+		catalogDirPath, err := GetCatalogDirectoryPath(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.MkdirAll(filepath.Dir(catalogDirPath), 0755)
+		if err != nil {
+			return nil, err
+		}
+
+		err = replaceDirectory(extractedDirPath, catalogDirPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to move %s catalog into place - %v", entry.Name, err)
+		}
+
+		config.Catalogs = upsertCatalogSource(config.Catalogs, entry.CatalogSource)
+	}
+
+	config.AppsLastUpdatedAt = bundle.AppsLastUpdatedAt
+	err = WriteConfigFile(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// upsertCatalogSource replaces the catalog with the same name, or appends it
+func upsertCatalogSource(sources []CatalogSource, source CatalogSource) []CatalogSource {
+	for i, s := range sources {
+		if s.Name == source.Name {
+			sources[i] = source
+			return sources
+		}
+	}
+
+	return append(sources, source)
+}
+
+// extractTarGz will extract the .tar.gz file into directory. Entries pointing
+// outside of the directory and symbolic links are rejected, as a chain of links
+// can lead outside of the directory even when each of them looks safe.
+func extractTarGz(filePath, directory string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		targetPath := filepath.Join(directory, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(targetPath, filepath.Clean(directory)+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(targetPath, os.FileMode(header.Mode))
+		case tar.TypeSymlink, tar.TypeLink:
+			return fmt.Errorf("illegal link %s in the bundle", header.Name)
+		case tar.TypeReg:
+			err = writeFileFromReader(targetPath, tarReader, os.FileMode(header.Mode))
+		default:
+			DebugPrintf("Skipping %s (type %c) in the bundle\n", header.Name, header.Typeflag)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeFileFromReader will create the file (and its parent folders) with reader's content
+func writeFileFromReader(filePath string, reader io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, reader)
+	return err
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestExportImportCatalogBundle(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, expectedHash := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	_ = WriteConfigFile(&KubemartConfigFile{
+		AppsLastUpdatedAt: 1600000000,
+		Catalogs:          []CatalogSource{{Name: DefaultCatalogName, URL: sourceDir}},
+	})
+	_ = CloneCatalog(CatalogSource{Name: DefaultCatalogName, URL: sourceDir})
+
+	bundleDir, _ := ioutil.TempDir("", "kubemart-bundle")
+	defer os.RemoveAll(bundleDir)
+	bundleFilePath := filepath.Join(bundleDir, "catalogs.tar.gz")
+
+	bundle, err := ExportCatalogBundle(bundleFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bundle.Catalogs) != 1 || bundle.Catalogs[0].Commit != expectedHash {
+		t.Errorf("Expected 1 catalog at %s but actual is %+v", expectedHash, bundle.Catalogs)
+	}
+
+	// start from scratch, as if it's a different machine
+	_ = os.RemoveAll(kp.RootDirectoryPath)
+
+	_, err = ImportCatalogBundle(bundleFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualHash, _ := GitLatestCommitHash(kp.AppsDirectoryPath)
+	if expectedHash != actualHash {
+		t.Errorf("Expected %s but actual is %s", expectedHash, actualHash)
+	}
+
+	timestamp := GetConfigFileLastUpdatedTimestamp()
+	if timestamp != 1600000000 {
+		t.Errorf("Expected 1600000000 but actual is %d", timestamp)
+	}
+}
+
+func TestImportCatalogBundleNotABundle(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	bundleDir, _ := ioutil.TempDir("", "kubemart-bundle")
+	defer os.RemoveAll(bundleDir)
+	bundleFilePath := filepath.Join(bundleDir, "catalogs.tar.gz")
+	_ = ioutil.WriteFile(bundleFilePath, []byte("not a bundle"), 0644)
+
+	_, err := ImportCatalogBundle(bundleFilePath)
+	if err == nil {
+		t.Errorf("Expected an error when importing an invalid bundle")
+	}
+}
+
+func TestExtractTarGzRejectsLinks(t *testing.T) {
+	bundleDir, _ := ioutil.TempDir("", "kubemart-bundle")
+	defer os.RemoveAll(bundleDir)
+	bundleFilePath := filepath.Join(bundleDir, "catalogs.tar.gz")
+
+	// each link looks safe on its own, but b/c leads to the parent of the directory
+	file, _ := os.Create(bundleFilePath)
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	_ = tarWriter.WriteHeader(&tar.Header{Name: "b", Typeflag: tar.TypeSymlink, Linkname: "."})
+	_ = tarWriter.WriteHeader(&tar.Header{Name: "b/c", Typeflag: tar.TypeSymlink, Linkname: "../x"})
+	_ = tarWriter.Close()
+	_ = gzipWriter.Close()
+	_ = file.Close()
+
+	extractDir := filepath.Join(bundleDir, "extracted")
+	_ = os.MkdirAll(extractDir, 0755)
+
+	err := extractTarGz(bundleFilePath, extractDir)
+	if err == nil {
+		t.Errorf("Expected an error when extracting links")
+	}
+
+	_, err = os.Lstat(filepath.Join(extractDir, "b"))
+	if !os.IsNotExist(err) {
+		t.Errorf("Expected no link to be created but got %v", err)
+	}
+}
+
+func TestOfflineModePreventsClone(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, _ := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	_ = SetOfflineMode(true)
+
+	err := CloneCatalog(CatalogSource{Name: DefaultCatalogName, URL: sourceDir})
+	if err == nil {
+		t.Errorf("Expected an error when cloning in offline mode")
+	}
+
+	ok, err := UpdateAppsCacheIfStale()
+	if !ok || err != nil {
+		t.Errorf("Expected refresh to be skipped in offline mode but got %t, %v", ok, err)
+	}
+}
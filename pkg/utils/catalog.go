@@ -0,0 +1,497 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultCatalogName is the name of the catalog that is registered
+// when ~/.kubemart/config.json does not declare any catalog
+const DefaultCatalogName = "default"
+
+// CatalogSource is an app catalog (a Git repository containing marketplace apps)
+// registered in ~/.kubemart/config.json
+type CatalogSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Ref  string `json:"ref"`
+	// Pin is the commit the catalog is held at, it's not refreshed when set
+	Pin string `json:"pin,omitempty"`
+}
+
+// GetDefaultCatalogSource returns the catalog that kubemart uses out of the box
+func GetDefaultCatalogSource() CatalogSource {
+	return CatalogSource{
+		Name: DefaultCatalogName,
+		URL:  fmt.Sprintf("https://github.com/%s/kubernetes-marketplace.git", marketplaceAccount),
+		Ref:  marketplaceBranch,
+	}
+}
+
+// ReadConfigFile will load ~/.kubemart/config.json file. A missing or empty file
+// is not an error, in that case the returned config contains the default catalog.
+func ReadConfigFile() (*KubemartConfigFile, error) {
+	config := &KubemartConfigFile{}
+
+	bp, err := GetKubemartPaths()
+	if err != nil {
+		return config, err
+	}
+
+	file, err := ioutil.ReadFile(bp.ConfigFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return config, err
+	}
+
+	if len(strings.TrimSpace(string(file))) > 0 {
+		err = json.Unmarshal(file, config)
+		if err != nil {
+			return config, fmt.Errorf("unable to parse %s file - %v", bp.ConfigFilePath, err)
+		}
+	}
+
+	// `null` (or missing) catalogs means the user never touched them
+	if config.Catalogs == nil {
+		config.Catalogs = []CatalogSource{GetDefaultCatalogSource()}
+	}
+
+	return config, nil
+}
+
+// WriteConfigFile will save the config into ~/.kubemart/config.json file
+func WriteConfigFile(config *KubemartConfigFile) error {
+	bp, err := GetKubemartPaths()
+	if err != nil {
+		return err
+	}
+
+	file, err := json.MarshalIndent(config, "", " ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(bp.ConfigFilePath, file, 0644)
+}
+
+// GetCatalogSources returns all registered catalogs, in lookup order
+func GetCatalogSources() ([]CatalogSource, error) {
+	config, err := ReadConfigFile()
+	if err != nil {
+		return []CatalogSource{}, err
+	}
+
+	return config.Catalogs, nil
+}
+
+// GetCatalogSource returns the registered catalog with the given name
+func GetCatalogSource(name string) (CatalogSource, error) {
+	sources, err := GetCatalogSources()
+	if err != nil {
+		return CatalogSource{}, err
+	}
+
+	for _, source := range sources {
+		if source.Name == name {
+			return source, nil
+		}
+	}
+
+	return CatalogSource{}, fmt.Errorf("catalog %s is not registered", name)
+}
+
+// GetCatalogDirectoryPath returns the folder where the catalog is cloned into.
+// The default catalog lives in ~/.kubemart/apps, others in ~/.kubemart/catalogs/NAME.
+func GetCatalogDirectoryPath(name string) (string, error) {
+	bp, err := GetKubemartPaths()
+	if err != nil {
+		return "", err
+	}
+
+	if name == DefaultCatalogName {
+		return bp.AppsDirectoryPath, nil
+	}
+
+	return filepath.Join(bp.CatalogsDirectoryPath, name), nil
+}
+
+// IsValidCatalogName returns true if the name can be used as a folder name
+// and as the SOURCE part of SOURCE/APP_NAME reference
+func IsValidCatalogName(name string) bool {
+	r := regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	return r.MatchString(name)
+}
+
+// AddCatalogSource will clone the catalog and register it in ~/.kubemart/config.json
+func AddCatalogSource(source CatalogSource) error {
+	if !IsValidCatalogName(source.Name) {
+		return fmt.Errorf("invalid catalog name %q - only lowercase letters, numbers and '-' are allowed", source.Name)
+	}
+
+	config, err := ReadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range config.Catalogs {
+		if s.Name == source.Name {
+			return fmt.Errorf("catalog %s is already registered", source.Name)
+		}
+	}
+
+	err = CloneCatalog(source)
+	if err != nil {
+		return err
+	}
+
+	config.Catalogs = append(config.Catalogs, source)
+	return WriteConfigFile(config)
+}
+
+// RemoveCatalogSource will unregister the catalog and delete its folder.
+// The default catalog can't be removed, the apps without catalog prefix fall back to it.
+func RemoveCatalogSource(name string) error {
+	if name == DefaultCatalogName {
+		return fmt.Errorf("%s catalog can't be removed - the apps without catalog prefix are looked up in it", name)
+	}
+
+	config, err := ReadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	catalogs := []CatalogSource{}
+	for _, s := range config.Catalogs {
+		if s.Name == name {
+			found = true
+			continue
+		}
+		catalogs = append(catalogs, s)
+	}
+
+	if !found {
+		return fmt.Errorf("catalog %s is not registered", name)
+	}
+
+	catalogDirPath, err := GetCatalogDirectoryPath(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(catalogDirPath)
+	if err != nil {
+		return fmt.Errorf("unable to delete %s directory - %v", catalogDirPath, err)
+	}
+
+	config.Catalogs = catalogs
+	return WriteConfigFile(config)
+}
+
+// ParseAppRef takes app reference in APP_NAME or SOURCE/APP_NAME format
+// and returns the catalog name (empty if not given) and the app name
+func ParseAppRef(appRef string) (string, string) {
+	splitted := strings.SplitN(appRef, "/", 2)
+	if len(splitted) == 2 {
+		return splitted[0], splitted[1]
+	}
+
+	return "", appRef
+}
+
+// GetAppDirectoryPath returns the app folder for the given app reference.
+// When the reference does not contain the catalog name, local apps and overlay
+// directories are searched first, then catalogs in the order they are registered.
+// The first match wins.
+func GetAppDirectoryPath(appRef string) (string, error) {
+	_, appDirPath, err := ResolveApp(appRef)
+	return appDirPath, err
+}
+
+// ResolveApp is like GetAppDirectoryPath but also returns the name of the
+// catalog the app was found in, OverlayCatalogName for a local app or an overlay app
+func ResolveApp(appRef string) (string, string, error) {
+	catalogName, appName := ParseAppRef(appRef)
+	if appName == "" {
+		return "", "", fmt.Errorf("app name is empty")
+	}
+
+	if catalogName == "" {
+		_, appDirPath, found, err := resolveOverlayApp(appName)
+		if err != nil {
+			return "", "", err
+		}
+		if found {
+			return OverlayCatalogName, appDirPath, nil
+		}
+	}
+
+	sources, err := GetCatalogSources()
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, source := range sources {
+		if catalogName != "" && source.Name != catalogName {
+			continue
+		}
+
+		catalogDirPath, err := GetCatalogDirectoryPath(source.Name)
+		if err != nil {
+			return "", "", err
+		}
+
+		appDirPath := filepath.Join(catalogDirPath, appName)
+		fileInfo, err := os.Stat(appDirPath)
+		if err == nil && fileInfo.IsDir() {
+			return source.Name, appDirPath, nil
+		}
+	}
+
+	if catalogName != "" {
+		return "", "", fmt.Errorf("unable to find %s app in %s catalog", appName, catalogName)
+	}
+
+	return "", "", fmt.Errorf("unable to find %s app", appName)
+}
+
+// IsCatalogCloned returns true if the catalog folder is a usable Git repository
+func IsCatalogCloned(name string) bool {
+	catalogDirPath, err := GetCatalogDirectoryPath(name)
+	if err != nil {
+		return false
+	}
+
+	_, err = GitLatestCommitHash(catalogDirPath)
+	return err == nil
+}
+
+// replaceDirectory moves newDirPath to dirPath. The current dirPath is moved aside first
+// and restored if the move fails, so it's only deleted once replaced.
+func replaceDirectory(newDirPath, dirPath string) error {
+	asideDirPath, err := ioutil.TempDir(filepath.Dir(dirPath), ".previous-")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary directory - %v", err)
+	}
+	defer os.Remove(asideDirPath)
+	previousDirPath := filepath.Join(asideDirPath, filepath.Base(dirPath))
+
+	err = os.Rename(dirPath, previousDirPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to move %s directory aside - %v", dirPath, err)
+	}
+	hasPrevious := err == nil
+
+	err = os.Rename(newDirPath, dirPath)
+	if err != nil {
+		if hasPrevious {
+			restoreErr := os.Rename(previousDirPath, dirPath)
+			if restoreErr != nil {
+				return fmt.Errorf("%v - unable to restore %s directory, it was moved to %s - %v", err, dirPath, previousDirPath, restoreErr)
+			}
+		}
+		return err
+	}
+
+	if hasPrevious {
+		err = os.RemoveAll(previousDirPath)
+		if err != nil {
+			DebugPrintf("Unable to delete %s directory - %v\n", previousDirPath, err)
+		}
+	}
+	return nil
+}
+
+// CloneCatalog will clone the catalog into a temporary folder and only move it
+// into the catalog folder once the clone succeeds. Whatever was in the catalog
+// folder before (e.g. a broken clone) is replaced.
+func CloneCatalog(source CatalogSource) error {
+	if IsOfflineMode() {
+		errMsg := "offline mode is enabled - unable to clone %s catalog\n"
+		errMsg += "You can import it with 'kubemart catalog import' command"
+		return fmt.Errorf(errMsg, source.Name)
+	}
+
+	bp, err := GetKubemartPaths()
+	if err != nil {
+		return err
+	}
+
+	catalogDirPath, err := GetCatalogDirectoryPath(source.Name)
+	if err != nil {
+		return err
+	}
+
+	// the temporary folder must be on the same filesystem for the rename below
+	tmpDirPath, err := ioutil.TempDir(bp.RootDirectoryPath, ".clone-")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary directory - %v", err)
+	}
+	defer os.RemoveAll(tmpDirPath)
+
+	clonedDirPath := filepath.Join(tmpDirPath, source.Name)
+	cloneOutput, err := GitClone(source.URL, source.Ref, clonedDirPath)
+	if err != nil {
+		return fmt.Errorf("unable to clone %s catalog - %v", source.Name, err)
+	}
+	DebugPrintf("Clone output: %s\n", cloneOutput)
+
+	if source.Pin != "" {
+		err = GitCheckout(clonedDirPath, source.Pin)
+		if err != nil {
+			return fmt.Errorf("unable to check out %s catalog at %s - %v", source.Name, source.Pin, err)
+		}
+	}
+
+	err = os.MkdirAll(filepath.Dir(catalogDirPath), 0755)
+	if err != nil {
+		return fmt.Errorf("unable to create %s directory - %v", filepath.Dir(catalogDirPath), err)
+	}
+
+	err = replaceDirectory(clonedDirPath, catalogDirPath)
+	if err != nil {
+		return fmt.Errorf("unable to move %s catalog into place - %v", source.Name, err)
+	}
+
+	return nil
+}
+
+// RepairCatalogs will re-create ~/.kubemart/config.json file when it's missing,
+// empty or corrupt, and re-clone every catalog whose folder is not a usable
+// Git repository. It returns the list of repairs that were made.
+func RepairCatalogs() ([]string, error) {
+	repairs := []string{}
+
+	bp, err := GetKubemartPaths()
+	if err != nil {
+		return repairs, err
+	}
+
+	err = os.MkdirAll(bp.RootDirectoryPath, 0755)
+	if err != nil {
+		return repairs, fmt.Errorf("unable to create %s directory - %v", bp.RootDirectoryPath, err)
+	}
+
+	config, err := ReadConfigFile()
+	if err != nil {
+		DebugPrintf("Config file is corrupt - %v\n", err)
+
+		backupFilePath := fmt.Sprintf("%s.bak", bp.ConfigFilePath)
+		err = os.Rename(bp.ConfigFilePath, backupFilePath)
+		if err != nil {
+			return repairs, fmt.Errorf("unable to back up %s file - %v", bp.ConfigFilePath, err)
+		}
+
+		config = &KubemartConfigFile{
+			Catalogs: []CatalogSource{GetDefaultCatalogSource()},
+		}
+		repairs = append(repairs, fmt.Sprintf("re-created corrupt config file (old one saved as %s)", backupFilePath))
+	} else if !IsConfigFilePresent() {
+		repairs = append(repairs, "re-created missing config file")
+	}
+
+	err = WriteConfigFile(config)
+	if err != nil {
+		return repairs, fmt.Errorf("unable to write %s file - %v", bp.ConfigFilePath, err)
+	}
+
+	for _, source := range config.Catalogs {
+		if IsCatalogCloned(source.Name) {
+			continue
+		}
+
+		err = CloneCatalog(source)
+		if err != nil {
+			return repairs, err
+		}
+		repairs = append(repairs, fmt.Sprintf("re-cloned %s catalog", source.Name))
+
+		err = UpdateConfigFileLastUpdatedTimestamp()
+		if err != nil {
+			return repairs, fmt.Errorf("unable to config file's timestamp field - %v", err)
+		}
+	}
+
+	return repairs, nil
+}
+
+// IsConfigFilePresent returns true if ~/.kubemart/config.json file exists and is not empty
+func IsConfigFilePresent() bool {
+	bp, err := GetKubemartPaths()
+	if err != nil {
+		return false
+	}
+
+	file, err := ioutil.ReadFile(bp.ConfigFilePath)
+	if err != nil {
+		return false
+	}
+
+	return len(strings.TrimSpace(string(file))) > 0
+}
+
+// PinCatalog will check out the catalog at the given commit and save it in
+// ~/.kubemart/config.json file, so the catalog is no longer refreshed.
+// It returns the short hash of the pinned commit.
+func PinCatalog(name, commit string) (string, error) {
+	config, err := ReadConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	catalogDirPath, err := GetCatalogDirectoryPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	for i, source := range config.Catalogs {
+		if source.Name != name {
+			continue
+		}
+
+		err = GitCheckout(catalogDirPath, commit)
+		if err != nil {
+			return "", fmt.Errorf("unable to pin %s catalog at %s - %v", name, commit, err)
+		}
+
+		pinned, err := GitLatestCommitHash(catalogDirPath)
+		if err != nil {
+			return "", err
+		}
+
+		config.Catalogs[i].Pin = pinned
+		return pinned, WriteConfigFile(config)
+	}
+
+	return "", fmt.Errorf("catalog %s is not registered", name)
+}
+
+// UnpinCatalog will remove the pin of the catalog and clone it again,
+// so it follows its branch or tag again
+func UnpinCatalog(name string) error {
+	config, err := ReadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	for i, source := range config.Catalogs {
+		if source.Name != name {
+			continue
+		}
+
+		source.Pin = ""
+		err = CloneCatalog(source)
+		if err != nil {
+			return err
+		}
+
+		config.Catalogs[i] = source
+		return WriteConfigFile(config)
+	}
+
+	return fmt.Errorf("catalog %s is not registered", name)
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestParseAppRef1(t *testing.T) {
+	catalogName, appName := ParseAppRef("wordpress")
+	if catalogName != "" || appName != "wordpress" {
+		t.Errorf("Expected ('', wordpress) but actual is (%s, %s)", catalogName, appName)
+	}
+}
+
+func TestParseAppRef2(t *testing.T) {
+	catalogName, appName := ParseAppRef("internal/wordpress")
+	if catalogName != "internal" || appName != "wordpress" {
+		t.Errorf("Expected (internal, wordpress) but actual is (%s, %s)", catalogName, appName)
+	}
+}
+
+func TestIsValidCatalogName1(t *testing.T) {
+	names := []string{"default", "internal", "team-a", "fork2"}
+	for _, name := range names {
+		if !IsValidCatalogName(name) {
+			t.Errorf("Expected %s to be a valid catalog name", name)
+		}
+	}
+}
+
+func TestIsValidCatalogName2(t *testing.T) {
+	names := []string{"", "-internal", "team/a", "Fork", "..", "team_a"}
+	for _, name := range names {
+		if IsValidCatalogName(name) {
+			t.Errorf("Expected %s to be an invalid catalog name", name)
+		}
+	}
+}
+
+func TestRemoveDefaultCatalogSource(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, _ := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	source := CatalogSource{Name: DefaultCatalogName, URL: sourceDir}
+	_ = WriteConfigFile(&KubemartConfigFile{Catalogs: []CatalogSource{source}})
+	_ = CloneCatalog(source)
+
+	err := RemoveCatalogSource(DefaultCatalogName)
+	if err == nil {
+		t.Errorf("Expected an error when removing the default catalog")
+	}
+
+	sources, _ := GetCatalogSources()
+	if len(sources) != 1 || sources[0].Name != DefaultCatalogName {
+		t.Errorf("Expected default catalog to be still registered but got %+v", sources)
+	}
+
+	_, err = os.Stat(kp.AppsDirectoryPath)
+	if err != nil {
+		t.Errorf("Expected default catalog folder to be kept - %v", err)
+	}
+}
+
+func TestCloneCatalogFailureLeavesNothingBehind(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+
+	source := CatalogSource{Name: "broken", URL: filepath.Join(kp.RootDirectoryPath, "does-not-exist")}
+	err := CloneCatalog(source)
+	if err == nil {
+		t.Errorf("Expected an error when cloning a missing repository")
+	}
+
+	files, _ := ioutil.ReadDir(kp.RootDirectoryPath)
+	if len(files) != 0 {
+		t.Errorf("Expected %s to be empty but found %d file(s)", kp.RootDirectoryPath, len(files))
+	}
+}
+
+func TestReplaceDirectory(t *testing.T) {
+	dir := fixtures.WriteFiles(t, map[string]string{
+		"catalog/manifest.yaml": "version: 1.0.0\n",
+		"new/manifest.yaml":     "version: 2.0.0\n",
+	})
+	defer os.RemoveAll(dir)
+	catalogDirPath := filepath.Join(dir, "catalog")
+
+	// the current directory is kept when the new one can't be moved into place
+	err := replaceDirectory(filepath.Join(dir, "missing"), catalogDirPath)
+	if err == nil {
+		t.Errorf("Expected an error when the new directory is missing")
+	}
+
+	content, _ := ioutil.ReadFile(filepath.Join(catalogDirPath, "manifest.yaml"))
+	if string(content) != "version: 1.0.0\n" {
+		t.Errorf("Expected the current directory to be restored but got %q", content)
+	}
+
+	err = replaceDirectory(filepath.Join(dir, "new"), catalogDirPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ = ioutil.ReadFile(filepath.Join(catalogDirPath, "manifest.yaml"))
+	if string(content) != "version: 2.0.0\n" {
+		t.Errorf("Expected the new directory in place but got %q", content)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("Expected only the catalog directory to be left but found %d file(s)", len(files))
+	}
+}
+
+func TestRepairCatalogs(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, expectedHash := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+
+	// a non-git apps folder, as left behind by an interrupted clone
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.AppsDirectoryPath, 0755)
+	_ = WriteConfigFile(&KubemartConfigFile{
+		Catalogs: []CatalogSource{{Name: DefaultCatalogName, URL: sourceDir}},
+	})
+
+	if IsCatalogCloned(DefaultCatalogName) {
+		t.Errorf("Expected %s catalog to be reported as broken", DefaultCatalogName)
+	}
+
+	repairs, err := RepairCatalogs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repairs) != 1 {
+		t.Errorf("Expected 1 repair but actual is %d (%v)", len(repairs), repairs)
+	}
+
+	actualHash, _ := GitLatestCommitHash(kp.AppsDirectoryPath)
+	if expectedHash != actualHash {
+		t.Errorf("Expected %s but actual is %s", expectedHash, actualHash)
+	}
+}
+
+func TestRepairCatalogsCorruptConfigFile(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	_ = ioutil.WriteFile(kp.ConfigFilePath, []byte("{not json"), 0644)
+
+	_, err := ReadConfigFile()
+	if err == nil {
+		t.Errorf("Expected an error when reading corrupt config file")
+	}
+
+	// the default catalog can't be cloned here, but the config file must be fixed first
+	repairs, _ := RepairCatalogs()
+	if len(repairs) == 0 {
+		t.Errorf("Expected config file to be repaired")
+	}
+
+	config, err := ReadConfigFile()
+	if err != nil {
+		t.Errorf("Expected config file to be readable after repair but got %v", err)
+	}
+
+	if len(config.Catalogs) != 1 || config.Catalogs[0].Name != DefaultCatalogName {
+		t.Errorf("Expected only %s catalog but actual is %+v", DefaultCatalogName, config.Catalogs)
+	}
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CatalogChanges is the difference of apps between two revisions of a catalog
+type CatalogChanges struct {
+	Catalog           string
+	FromCommit        string
+	ToCommit          string
+	AddedApps         []string
+	RemovedApps       []string
+	VersionChanges    []AppChange
+	PlanChanges       []AppChange
+	DependencyChanges []AppChange
+}
+
+// AppChange is a field of an app that is different between two revisions
+type AppChange struct {
+	App  string
+	From string
+	To   string
+}
+
+// IsEmpty returns true when nothing has changed
+func (c *CatalogChanges) IsEmpty() bool {
+	return len(c.AddedApps) == 0 &&
+		len(c.RemovedApps) == 0 &&
+		len(c.VersionChanges) == 0 &&
+		len(c.PlanChanges) == 0 &&
+		len(c.DependencyChanges) == 0
+}
+
+// GetCatalogChanges compares the apps of the catalog at fromCommit with the
+// apps currently checked out. When fromCommit is empty, every app is new.
+func GetCatalogChanges(catalogName, fromCommit string) (*CatalogChanges, error) {
+	catalogDirPath, err := GetCatalogDirectoryPath(catalogName)
+	if err != nil {
+		return nil, err
+	}
+
+	toCommit, err := GitLatestCommitHash(catalogDirPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the commit of %s catalog - %v", catalogName, err)
+	}
+
+	from := make(map[string]AppManifest)
+	if fromCommit != "" {
+		from, err = readCatalogManifests(catalogDirPath, fromCommit)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s catalog at %s - %v", catalogName, fromCommit, err)
+		}
+	}
+
+	to, err := readCatalogManifests(catalogDirPath, toCommit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s catalog at %s - %v", catalogName, toCommit, err)
+	}
+
+	changes := &CatalogChanges{
+		Catalog:    catalogName,
+		FromCommit: fromCommit,
+		ToCommit:   toCommit,
+	}
+
+	for _, appName := range sortedManifestNames(to) {
+		newManifest := to[appName]
+		oldManifest, found := from[appName]
+		if !found {
+			changes.AddedApps = append(changes.AddedApps, appName)
+			continue
+		}
+
+		if oldManifest.Version != newManifest.Version {
+			changes.VersionChanges = append(changes.VersionChanges, AppChange{appName, oldManifest.Version, newManifest.Version})
+		}
+
+		oldPlans, newPlans := describePlans(oldManifest), describePlans(newManifest)
+		if oldPlans != newPlans {
+			changes.PlanChanges = append(changes.PlanChanges, AppChange{appName, oldPlans, newPlans})
+		}
+
+		oldDependencies := strings.Join(oldManifest.Dependencies, ", ")
+		newDependencies := strings.Join(newManifest.Dependencies, ", ")
+		if oldDependencies != newDependencies {
+			changes.DependencyChanges = append(changes.DependencyChanges, AppChange{appName, oldDependencies, newDependencies})
+		}
+	}
+
+	for _, appName := range sortedManifestNames(from) {
+		if _, found := to[appName]; !found {
+			changes.RemovedApps = append(changes.RemovedApps, appName)
+		}
+	}
+
+	return changes, nil
+}
+
+// GetCatalogChangesSince is like GetCatalogChanges but compares with the
+// last commit made at or before the given time
+func GetCatalogChangesSince(catalogName string, since time.Time) (*CatalogChanges, error) {
+	catalogDirPath, err := GetCatalogDirectoryPath(catalogName)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCommit, err := GetCatalogSyncer().CommitBefore(catalogDirPath, since)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the history of %s catalog - %v", catalogName, err)
+	}
+
+	return GetCatalogChanges(catalogName, fromCommit)
+}
+
+// GetCatalogChangesSinceLastRefresh is like GetCatalogChanges but compares
+// with the commit the catalog was at before it was last refreshed. It returns
+// nil when the catalog has never been refreshed.
+func GetCatalogChangesSinceLastRefresh(catalogName string) (*CatalogChanges, error) {
+	config, err := ReadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	fromCommit, found := config.PreviousCommits[catalogName]
+	if !found {
+		return nil, nil
+	}
+
+	return GetCatalogChanges(catalogName, fromCommit)
+}
+
+// readCatalogManifests returns manifests of all apps of the catalog at the given commit
+func readCatalogManifests(catalogDirPath, commit string) (map[string]AppManifest, error) {
+	manifests := make(map[string]AppManifest)
+
+	files, err := GetCatalogSyncer().ReadFiles(catalogDirPath, commit, "manifest.yaml")
+	if err != nil {
+		return manifests, err
+	}
+
+	for appName, file := range files {
+		manifest := AppManifest{}
+		err = yaml.Unmarshal(file, &manifest)
+		if err != nil {
+			DebugPrintf("Skipping %s app at %s, its manifest is invalid - %v\n", appName, commit, err)
+			continue
+		}
+		manifests[appName] = manifest
+	}
+
+	return manifests, nil
+}
+
+// describePlans returns the plans as a single line e.g. "5GB (VOLUME_SIZE=5Gi), 10GB (VOLUME_SIZE=10Gi)"
+func describePlans(manifest AppManifest) string {
+	plans := []string{}
+	for _, plan := range manifest.Plans {
+		configuration := []string{}
+		for key, value := range plan.Configuration {
+			configuration = append(configuration, fmt.Sprintf("%s=%s", key, value.Value))
+		}
+		sort.Strings(configuration)
+
+		if len(configuration) == 0 {
+			plans = append(plans, plan.Label)
+		} else {
+			plans = append(plans, fmt.Sprintf("%s (%s)", plan.Label, strings.Join(configuration, ", ")))
+		}
+	}
+
+	return strings.Join(plans, ", ")
+}
+
+func sortedManifestNames(manifests map[string]AppManifest) []string {
+	names := []string{}
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestGetCatalogChangesSinceLastRefresh(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, _ := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+	fixtures.AddCommit(t, sourceDir, "wordpress/manifest.yaml", "version: 1.0.0\ndependencies:\n- mariadb:5GB\n")
+	fixtures.AddCommit(t, sourceDir, "mariadb/manifest.yaml", "version: 1.0.0\nplans:\n- label: 5GB\n  configuration:\n    VOLUME_SIZE:\n      value: 5Gi\n")
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	source := CatalogSource{Name: DefaultCatalogName, URL: sourceDir}
+	_ = WriteConfigFile(&KubemartConfigFile{Catalogs: []CatalogSource{source}})
+	_ = CloneCatalog(source)
+
+	changes, _ := GetCatalogChangesSinceLastRefresh(DefaultCatalogName)
+	if changes != nil {
+		t.Errorf("Expected no changes before the first refresh but got %+v", changes)
+	}
+
+	fixtures.AddCommit(t, sourceDir, "wordpress/manifest.yaml", "version: 2.0.0\ndependencies:\n- mariadb:10GB\n")
+	fixtures.AddCommit(t, sourceDir, "mariadb/manifest.yaml", "version: 1.0.0\nplans:\n- label: 10GB\n  configuration:\n    VOLUME_SIZE:\n      value: 10Gi\n")
+	fixtures.AddCommit(t, sourceDir, "linkerd/manifest.yaml", "version: 1.0.0\n")
+
+	ok, err := UpdateAppsCacheIfStale()
+	if !ok {
+		t.Fatal(err)
+	}
+
+	changes, err = GetCatalogChangesSinceLastRefresh(DefaultCatalogName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !elementsMatch(changes.AddedApps, []string{"linkerd"}) || len(changes.RemovedApps) != 0 {
+		t.Errorf("Expected linkerd to be added but actual is %+v", changes)
+	}
+
+	expectedVersions := []AppChange{{"wordpress", "1.0.0", "2.0.0"}}
+	if !elementsMatch(changes.VersionChanges, expectedVersions) {
+		t.Errorf("Expected %+v but actual is %+v", expectedVersions, changes.VersionChanges)
+	}
+
+	expectedPlans := []AppChange{{"mariadb", "5GB (VOLUME_SIZE=5Gi)", "10GB (VOLUME_SIZE=10Gi)"}}
+	if !elementsMatch(changes.PlanChanges, expectedPlans) {
+		t.Errorf("Expected %+v but actual is %+v", expectedPlans, changes.PlanChanges)
+	}
+
+	expectedDependencies := []AppChange{{"wordpress", "mariadb:5GB", "mariadb:10GB"}}
+	if !elementsMatch(changes.DependencyChanges, expectedDependencies) {
+		t.Errorf("Expected %+v but actual is %+v", expectedDependencies, changes.DependencyChanges)
+	}
+}
+
+func TestGetCatalogChangesFromTheBeginning(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, _ := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+	fixtures.AddCommit(t, sourceDir, "wordpress/manifest.yaml", "version: 1.0.0\n")
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	source := CatalogSource{Name: DefaultCatalogName, URL: sourceDir}
+	_ = WriteConfigFile(&KubemartConfigFile{Catalogs: []CatalogSource{source}})
+	_ = CloneCatalog(source)
+
+	changes, err := GetCatalogChanges(DefaultCatalogName, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !elementsMatch(changes.AddedApps, []string{"wordpress"}) {
+		t.Errorf("Expected wordpress to be added but actual is %+v", changes)
+	}
+}
@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// LintSeverityError is used for problems that break the app
+	LintSeverityError = "error"
+	// LintSeverityWarning is used for problems that should be looked at
+	LintSeverityWarning = "warning"
+)
+
+// appNameRegex is what an app name (i.e. app folder) must look like
+var appNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// LintIssue is a problem found in an app of a catalog
+type LintIssue struct {
+	App      string `json:"app"`
+	File     string `json:"file"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// String returns the issue in "FILE: SEVERITY: MESSAGE" format
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.File, i.Severity, i.Message)
+}
+
+// LintCatalog validates every app of the catalog in the given directory. The
+// issues are sorted by app, the returned error is only for unreadable catalogs.
+func LintCatalog(catalogDirPath string) ([]LintIssue, error) {
+	issues := []LintIssue{}
+
+	files, err := ioutil.ReadDir(catalogDirPath)
+	if err != nil {
+		return issues, fmt.Errorf("unable to get list of files - %v", err)
+	}
+
+	manifests := make(map[string]AppManifest)
+	for _, file := range files {
+		appName := file.Name()
+		if !file.IsDir() || strings.HasPrefix(appName, ".") || appName == "bin" {
+			continue
+		}
+
+		manifest, appIssues := lintAppFiles(filepath.Join(catalogDirPath, appName))
+		issues = append(issues, appIssues...)
+		if manifest != nil {
+			manifests[appName] = *manifest
+		}
+	}
+
+	for _, appName := range sortedManifestNames(manifests) {
+		manifestFileName := filepath.Join(appName, "manifest.yaml")
+		for _, message := range lintManifest(appName, manifests) {
+			issues = append(issues, LintIssue{appName, manifestFileName, LintSeverityError, message})
+		}
+	}
+
+	for _, cycle := range findDependencyCycles(manifests) {
+		appName := cycle[0]
+		message := fmt.Sprintf("circular dependency %s", strings.Join(cycle, " -> "))
+		issues = append(issues, LintIssue{appName, filepath.Join(appName, "manifest.yaml"), LintSeverityError, message})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].App < issues[j].App
+	})
+
+	return issues, nil
+}
+
+// LintApp validates the app in the given folder. Its dependencies are looked up
+// in the other apps (e.g. from the catalogs), keyed by app name.
+func LintApp(appDirPath string, otherApps map[string]AppManifest) ([]LintIssue, error) {
+	fileInfo, err := os.Stat(appDirPath)
+	if err != nil || !fileInfo.IsDir() {
+		return []LintIssue{}, fmt.Errorf("%s is not a directory", appDirPath)
+	}
+
+	appName := filepath.Base(filepath.Clean(appDirPath))
+	manifest, issues := lintAppFiles(appDirPath)
+	if manifest == nil {
+		return issues, nil
+	}
+
+	manifests := make(map[string]AppManifest)
+	for name, otherApp := range otherApps {
+		manifests[name] = otherApp
+	}
+	manifests[appName] = *manifest
+
+	manifestFileName := filepath.Join(appName, "manifest.yaml")
+	for _, message := range lintManifest(appName, manifests) {
+		issues = append(issues, LintIssue{appName, manifestFileName, LintSeverityError, message})
+	}
+
+	for _, cycle := range findDependencyCycles(manifests) {
+		for _, name := range cycle {
+			if name == appName {
+				message := fmt.Sprintf("circular dependency %s", strings.Join(cycle, " -> "))
+				issues = append(issues, LintIssue{appName, manifestFileName, LintSeverityError, message})
+				break
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// lintAppFiles checks the files of the app folder. The returned manifest
+// is nil when manifest.yaml is missing or can't be parsed.
+func lintAppFiles(appDirPath string) (*AppManifest, []LintIssue) {
+	issues := []LintIssue{}
+	appName := filepath.Base(filepath.Clean(appDirPath))
+	manifestFileName := filepath.Join(appName, "manifest.yaml")
+
+	content, err := ioutil.ReadFile(filepath.Join(appDirPath, "manifest.yaml"))
+	if err != nil {
+		issues = append(issues, LintIssue{appName, manifestFileName, LintSeverityError, "manifest.yaml is missing"})
+		return nil, issues
+	}
+
+	manifest := &AppManifest{}
+	err = yaml.Unmarshal(content, manifest)
+	if err != nil {
+		issues = append(issues, LintIssue{appName, manifestFileName, LintSeverityError, fmt.Sprintf("unable to parse manifest.yaml - %v", err)})
+		return nil, issues
+	}
+
+	// fields the CLI does not know about are most likely typos
+	err = yaml.UnmarshalStrict(content, &AppManifest{})
+	if err != nil {
+		issues = append(issues, LintIssue{appName, manifestFileName, LintSeverityWarning, fmt.Sprintf("unexpected content in manifest.yaml - %v", err)})
+	}
+
+	if !appNameRegex.MatchString(appName) {
+		issues = append(issues, LintIssue{appName, appName, LintSeverityError, "app name must only contain lowercase letters, numbers and '-'"})
+	}
+
+	_, err = os.Stat(filepath.Join(appDirPath, "post_install.md"))
+	if err != nil {
+		issues = append(issues, LintIssue{appName, filepath.Join(appName, "post_install.md"), LintSeverityError, "post_install.md is missing"})
+	}
+
+	scripts, _ := filepath.Glob(filepath.Join(appDirPath, "*.sh"))
+	if len(scripts) == 0 {
+		issues = append(issues, LintIssue{appName, appName, LintSeverityWarning, "no install script (*.sh) found"})
+	}
+
+	return manifest, issues
+}
+
+// HasLintErrors returns true if any of the issues is an error
+func HasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// lintManifest returns the problems of the app's manifest, except dependency cycles
+func lintManifest(appName string, manifests map[string]AppManifest) []string {
+	messages := []string{}
+	manifest := manifests[appName]
+
+	if manifest.Version == "" {
+		messages = append(messages, "version is required")
+	}
+	if manifest.Namespace == "" {
+		messages = append(messages, "namespace is required")
+	}
+	if manifest.Category == "" {
+		messages = append(messages, "category is required")
+	}
+
+	// every plan must set the same configuration key(s), see GetAppPlanVariableName
+	labels := make(map[string]bool)
+	expectedKeys := ""
+	for i, plan := range manifest.Plans {
+		if plan.Label == "" {
+			messages = append(messages, fmt.Sprintf("plan #%d has no label", i+1))
+		} else if labels[plan.Label] {
+			messages = append(messages, fmt.Sprintf("plan %s is declared more than once", plan.Label))
+		}
+		labels[plan.Label] = true
+
+		keys := []string{}
+		for key := range plan.Configuration {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		if len(keys) == 0 {
+			messages = append(messages, fmt.Sprintf("plan %s has no configuration", plan.Label))
+			continue
+		}
+
+		if expectedKeys == "" {
+			expectedKeys = strings.Join(keys, ", ")
+		} else if strings.Join(keys, ", ") != expectedKeys {
+			messages = append(messages, fmt.Sprintf("plan %s configures %s but the first plan configures %s", plan.Label, strings.Join(keys, ", "), expectedKeys))
+		}
+	}
+
+	for _, dependency := range manifest.Dependencies {
+		splitted := strings.Split(dependency, ":")
+		if len(splitted) > 2 || strings.TrimSpace(splitted[0]) == "" || (len(splitted) == 2 && strings.TrimSpace(splitted[1]) == "") {
+			messages = append(messages, fmt.Sprintf("dependency %q must be in APP_NAME[:PLAN] format", dependency))
+			continue
+		}
+
+		name, plan := ParseDependency(dependency)
+		dependencyManifest, found := manifests[name]
+		if !found {
+			messages = append(messages, fmt.Sprintf("dependency %s does not exist in the catalog", name))
+			continue
+		}
+
+		if plan != "" && !hasPlan(dependencyManifest, plan) {
+			messages = append(messages, fmt.Sprintf("dependency %s does not have %s plan", name, plan))
+		}
+	}
+
+	return messages
+}
+
+func hasPlan(manifest AppManifest, label string) bool {
+	for _, plan := range manifest.Plans {
+		if plan.Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+// findDependencyCycles returns the dependency cycles between the apps. Each cycle
+// starts and ends with the same app e.g. [a b a] and is only reported once.
+func findDependencyCycles(manifests map[string]AppManifest) [][]string {
+	cycles := [][]string{}
+	done := make(map[string]bool)
+	reported := make(map[string]bool)
+
+	var visit func(appName string, path []string)
+	visit = func(appName string, path []string) {
+		for i, name := range path {
+			if name != appName {
+				continue
+			}
+
+			cycle := append(append([]string{}, path[i:]...), appName)
+			if !reported[appName] {
+				for _, name := range path[i:] {
+					reported[name] = true
+				}
+				cycles = append(cycles, cycle)
+			}
+			return
+		}
+
+		if done[appName] {
+			return
+		}
+
+		manifest, found := manifests[appName]
+		if !found {
+			return
+		}
+
+		for _, dependency := range manifest.Dependencies {
+			name, _ := ParseDependency(dependency)
+			visit(name, append(path, appName))
+		}
+		done[appName] = true
+	}
+
+	for _, appName := range sortedManifestNames(manifests) {
+		visit(appName, []string{})
+	}
+
+	return cycles
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestLintCatalogValid(t *testing.T) {
+	dir := fixtures.WriteFiles(t, map[string]string{
+		"wordpress/manifest.yaml":   "namespace: wordpress\nversion: 5.7\ncategory: management\ndependencies:\n- MariaDB:5GB\n",
+		"wordpress/post_install.md": "# WordPress\n",
+		"wordpress/install.sh":      "#!/bin/bash\n",
+		"mariadb/manifest.yaml":     "namespace: mariadb\nversion: 10.5\ncategory: database\nplans:\n- label: 5GB\n  configuration:\n    VOLUME_SIZE:\n      value: 5Gi\n",
+		"mariadb/post_install.md":   "# MariaDB\n",
+		"mariadb/install.sh":        "#!/bin/bash\n",
+		"bin/manifest.yaml":         "not: an app\n",
+	})
+	defer os.RemoveAll(dir)
+
+	issues, err := LintCatalog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 0 {
+		t.Errorf("Expected no issues but got %+v", issues)
+	}
+}
+
+func TestLintCatalogInvalid(t *testing.T) {
+	dir := fixtures.WriteFiles(t, map[string]string{
+		"wordpress/manifest.yaml": "namespace: wordpress\nversion: 5.7\ncategory: management\ndependencies:\n- mariadb:50GB\n- redis\n- \"mariadb:\"\n",
+		"mariadb/manifest.yaml":   "namespace: mariadb\ncategory: database\ndependencies:\n- wordpress\nplans:\n- label: 5GB\n  configuration:\n    VOLUME_SIZE:\n      value: 5Gi\n- label: 10GB\n  configuration:\n    SIZE:\n      value: 10Gi\n",
+		"mariadb/post_install.md": "# MariaDB\n",
+		"linkerd/manifest.yaml":   "namespace: linkerd\nversion: 2.10\ncategory: architecture\nvesrion: 2.11\n",
+		"linkerd/post_install.md": "# Linkerd\n",
+	})
+	defer os.RemoveAll(dir)
+
+	issues, err := LintCatalog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []string{}
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	actual := strings.Join(messages, "\n")
+
+	expectedMessages := []string{
+		"linkerd/manifest.yaml: warning: unexpected content in manifest.yaml",
+		"mariadb/manifest.yaml: error: version is required",
+		"mariadb/manifest.yaml: error: plan 10GB configures SIZE but the first plan configures VOLUME_SIZE",
+		"wordpress/post_install.md: error: post_install.md is missing",
+		"wordpress/manifest.yaml: error: dependency mariadb does not have 50GB plan",
+		"wordpress/manifest.yaml: error: dependency redis does not exist in the catalog",
+		"wordpress/manifest.yaml: error: dependency \"mariadb:\" must be in APP_NAME[:PLAN] format",
+		"error: circular dependency mariadb -> wordpress -> mariadb",
+	}
+	for _, expected := range expectedMessages {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expected %q in issues but got:\n%s", expected, actual)
+		}
+	}
+
+	if !HasLintErrors(issues) {
+		t.Errorf("Expected errors to be found")
+	}
+}
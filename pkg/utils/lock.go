@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultLockFileName is the lock file looked up in the current directory
+const DefaultLockFileName = "kubemart.lock"
+
+// KubemartLockFile is the structure of kubemart.lock file. It records the commit
+// of every catalog and the version of every app installed with it, so installs
+// are reproducible.
+type KubemartLockFile struct {
+	Catalogs map[string]string `json:"catalogs"`
+	Apps     map[string]string `json:"apps"`
+}
+
+// ReadLockFile will load the lock file. The returned bool is 'false' when
+// the lock file does not exist.
+func ReadLockFile(lockFilePath string) (*KubemartLockFile, bool, error) {
+	lock := &KubemartLockFile{
+		Catalogs: make(map[string]string),
+		Apps:     make(map[string]string),
+	}
+
+	file, err := ioutil.ReadFile(lockFilePath)
+	if os.IsNotExist(err) {
+		return lock, false, nil
+	}
+	if err != nil {
+		return lock, false, err
+	}
+
+	err = json.Unmarshal(file, lock)
+	if err != nil {
+		return lock, true, fmt.Errorf("unable to parse %s file - %v", lockFilePath, err)
+	}
+
+	if lock.Catalogs == nil {
+		lock.Catalogs = make(map[string]string)
+	}
+	if lock.Apps == nil {
+		lock.Apps = make(map[string]string)
+	}
+
+	return lock, true, nil
+}
+
+// WriteLockFile will save the lock file
+func WriteLockFile(lockFilePath string, lock *KubemartLockFile) error {
+	file, err := json.MarshalIndent(lock, "", " ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(lockFilePath, append(file, '\n'), 0644)
+}
+
+// IsSameCommit returns true if both hashes point to the same commit.
+// Either of them can be abbreviated.
+func IsSameCommit(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	a = strings.ToLower(a)
+	b = strings.ToLower(b)
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// RecordCatalogs will save the current commit of every catalog into the lock
+func (lock *KubemartLockFile) RecordCatalogs() error {
+	sources, err := GetCatalogSources()
+	if err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		catalogDirPath, err := GetCatalogDirectoryPath(source.Name)
+		if err != nil {
+			return err
+		}
+
+		commit, err := GitLatestCommitHash(catalogDirPath)
+		if err != nil {
+			return fmt.Errorf("unable to get the commit of %s catalog - %v", source.Name, err)
+		}
+
+		lock.Catalogs[source.Name] = commit
+	}
+
+	return nil
+}
+
+// RecordApps will save the catalog version of the given apps into the lock
+func (lock *KubemartLockFile) RecordApps(appNames []string) error {
+	for _, appName := range appNames {
+		manifest, err := GetAppManifest(appName)
+		if err != nil {
+			return fmt.Errorf("unable to load %s app manifest - %v", appName, err)
+		}
+
+		lock.Apps[appName] = manifest.Version
+	}
+
+	return nil
+}
+
+// Verify returns an error if the local catalogs or the given apps
+// do not match what's recorded in the lock
+func (lock *KubemartLockFile) Verify(appNames []string) error {
+	mismatches := []string{}
+
+	catalogNames := []string{}
+	for catalogName := range lock.Catalogs {
+		catalogNames = append(catalogNames, catalogName)
+	}
+	sort.Strings(catalogNames)
+
+	for _, catalogName := range catalogNames {
+		expected := lock.Catalogs[catalogName]
+		catalogDirPath, err := GetCatalogDirectoryPath(catalogName)
+		if err != nil {
+			return err
+		}
+
+		actual, err := GitLatestCommitHash(catalogDirPath)
+		if err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("%s catalog is locked at %s but it's not available locally", catalogName, expected))
+			continue
+		}
+
+		if !IsSameCommit(expected, actual) {
+			mismatches = append(mismatches, fmt.Sprintf("%s catalog is locked at %s but local catalog is at %s", catalogName, expected, actual))
+		}
+	}
+
+	for _, appName := range appNames {
+		expected, found := lock.Apps[appName]
+		if !found {
+			continue
+		}
+
+		manifest, err := GetAppManifest(appName)
+		if err != nil {
+			return fmt.Errorf("unable to load %s app manifest - %v", appName, err)
+		}
+
+		if manifest.Version != expected {
+			mismatches = append(mismatches, fmt.Sprintf("%s app is locked at version %s but local catalog has %s", appName, expected, manifest.Version))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("local catalogs do not match the lock file:\n* %s", strings.Join(mismatches, "\n* "))
+	}
+
+	return nil
+}
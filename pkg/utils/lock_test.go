@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestIsSameCommit1(t *testing.T) {
+	if !IsSameCommit("1a2b3c4", "1a2b3c4d5e6f") {
+		t.Errorf("Expected abbreviated hash to match the full hash")
+	}
+}
+
+func TestIsSameCommit2(t *testing.T) {
+	if IsSameCommit("1a2b3c4", "1a2b3c5") {
+		t.Errorf("Expected different hashes not to match")
+	}
+
+	if IsSameCommit("", "1a2b3c4") {
+		t.Errorf("Expected empty hash not to match")
+	}
+}
+
+func TestPinCatalogAndVerifyLock(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, firstHash := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+	fixtures.AddCommit(t, sourceDir, "wordpress/manifest.yaml", "version: 1.0.0\n")
+	secondHash := fixtures.AddCommit(t, sourceDir, "wordpress/manifest.yaml", "version: 2.0.0\n")
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	source := CatalogSource{Name: DefaultCatalogName, URL: sourceDir}
+	_ = WriteConfigFile(&KubemartConfigFile{Catalogs: []CatalogSource{source}})
+	_ = CloneCatalog(source)
+
+	lockDir, _ := ioutil.TempDir("", "kubemart-lock")
+	defer os.RemoveAll(lockDir)
+	lockFilePath := filepath.Join(lockDir, DefaultLockFileName)
+
+	lock, found, _ := ReadLockFile(lockFilePath)
+	if found {
+		t.Errorf("Expected %s not to exist", lockFilePath)
+	}
+
+	_ = lock.RecordCatalogs()
+	_ = lock.RecordApps([]string{"wordpress"})
+	_ = WriteLockFile(lockFilePath, lock)
+
+	lock, _, _ = ReadLockFile(lockFilePath)
+	if lock.Catalogs[DefaultCatalogName] != secondHash || lock.Apps["wordpress"] != "2.0.0" {
+		t.Errorf("Expected lock at %s with wordpress 2.0.0 but actual is %+v", secondHash, lock)
+	}
+
+	err := lock.Verify([]string{"wordpress"})
+	if err != nil {
+		t.Errorf("Expected lock to match but got %v", err)
+	}
+
+	pinned, err := PinCatalog(DefaultCatalogName, firstHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pinned != firstHash {
+		t.Errorf("Expected %s but actual is %s", firstHash, pinned)
+	}
+
+	err = lock.Verify([]string{})
+	if err == nil {
+		t.Errorf("Expected lock not to match after pinning another commit")
+	}
+
+	pinnedSource, _ := GetCatalogSource(DefaultCatalogName)
+	if pinnedSource.Pin != firstHash {
+		t.Errorf("Expected pin %s to be saved but actual is %s", firstHash, pinnedSource.Pin)
+	}
+}
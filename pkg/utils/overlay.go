@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// OverlayCatalogName is the catalog name of the apps of a local folder or an overlay directory
+const OverlayCatalogName = "overlay"
+
+// localApps are app folders given with 'install --from', keyed by app name
+var localApps = make(map[string]string)
+
+// AddLocalApp makes the app folder available (until the program exits) as if it
+// was in a catalog, ahead of the catalogs. It returns the app name i.e. folder name.
+func AddLocalApp(appDirPath string) (string, error) {
+	appDirPath, err := filepath.Abs(appDirPath)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = os.Stat(filepath.Join(appDirPath, "manifest.yaml"))
+	if err != nil {
+		return "", fmt.Errorf("%s is not an app folder - %v", appDirPath, err)
+	}
+
+	appName := filepath.Base(appDirPath)
+	localApps[appName] = appDirPath
+	return appName, nil
+}
+
+// GetOverlayDirectories returns the registered overlay directories. Apps (i.e.
+// sub folders) of an overlay directory take precedence over the catalogs.
+func GetOverlayDirectories() ([]string, error) {
+	config, err := ReadConfigFile()
+	if err != nil {
+		return []string{}, err
+	}
+
+	return config.Overlays, nil
+}
+
+// AddOverlayDirectory will register the directory as an overlay
+func AddOverlayDirectory(directory string) (string, error) {
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return "", err
+	}
+
+	fileInfo, err := os.Stat(directory)
+	if err != nil || !fileInfo.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", directory)
+	}
+
+	config, err := ReadConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	for _, overlay := range config.Overlays {
+		if overlay == directory {
+			return "", fmt.Errorf("%s is already an overlay directory", directory)
+		}
+	}
+
+	config.Overlays = append(config.Overlays, directory)
+	return directory, WriteConfigFile(config)
+}
+
+// RemoveOverlayDirectory will unregister the overlay directory. The directory itself is kept.
+func RemoveOverlayDirectory(directory string) (string, error) {
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := ReadConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	overlays := []string{}
+	for _, overlay := range config.Overlays {
+		if overlay != directory {
+			overlays = append(overlays, overlay)
+		}
+	}
+
+	if len(overlays) == len(config.Overlays) {
+		return "", fmt.Errorf("%s is not an overlay directory", directory)
+	}
+
+	config.Overlays = overlays
+	return directory, WriteConfigFile(config)
+}
+
+// IsOverlayApp returns true when the app comes from a local folder ('install --from')
+// or an overlay directory rather than a catalog
+func IsOverlayApp(appRef string) (bool, error) {
+	catalogName, appName := ParseAppRef(appRef)
+	if catalogName != "" {
+		return false, nil
+	}
+
+	_, _, found, err := resolveOverlayApp(appName)
+	return found, err
+}
+
+// resolveOverlayApp returns the folder of the app when it's a local app or it's
+// in one of the overlay directories. The returned bool is 'false' when it's not.
+func resolveOverlayApp(appName string) (string, string, bool, error) {
+	if appDirPath, found := localApps[appName]; found {
+		return filepath.Dir(appDirPath), appDirPath, true, nil
+	}
+
+	overlays, err := GetOverlayDirectories()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	for _, overlay := range overlays {
+		appDirPath := filepath.Join(overlay, appName)
+		fileInfo, err := os.Stat(appDirPath)
+		if err == nil && fileInfo.IsDir() {
+			return overlay, appDirPath, true, nil
+		}
+	}
+
+	return "", "", false, nil
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestOverlayDirectoryTakesPrecedence(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	sourceDir, _ := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+	fixtures.AddCommit(t, sourceDir, "wordpress/manifest.yaml", "version: 1.0.0\n")
+
+	kp, _ := GetKubemartPaths()
+	_ = os.MkdirAll(kp.RootDirectoryPath, 0755)
+	source := CatalogSource{Name: DefaultCatalogName, URL: sourceDir}
+	_ = WriteConfigFile(&KubemartConfigFile{Catalogs: []CatalogSource{source}})
+	_ = CloneCatalog(source)
+
+	overlayDir := fixtures.WriteFiles(t, map[string]string{
+		"wordpress/manifest.yaml": "version: 2.0.0-dev\n",
+	})
+	defer os.RemoveAll(overlayDir)
+
+	_, err := AddOverlayDirectory(overlayDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, _ := GetAppManifest("wordpress")
+	if manifest.Version != "2.0.0-dev" {
+		t.Errorf("Expected overlay's wordpress 2.0.0-dev but actual is %s", manifest.Version)
+	}
+
+	catalogName, _, _ := ResolveApp("wordpress")
+	isOverlayApp, _ := IsOverlayApp("wordpress")
+	if catalogName != OverlayCatalogName || !isOverlayApp {
+		t.Errorf("Expected wordpress to be an overlay app but got %s catalog", catalogName)
+	}
+
+	isOverlayApp, _ = IsOverlayApp("default/wordpress")
+	if isOverlayApp {
+		t.Errorf("Expected default/wordpress not to be an overlay app")
+	}
+
+	manifest, _ = GetAppManifest("default/wordpress")
+	if manifest.Version != "1.0.0" {
+		t.Errorf("Expected catalog's wordpress 1.0.0 but actual is %s", manifest.Version)
+	}
+
+	_, err = RemoveOverlayDirectory(overlayDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, _ = GetAppManifest("wordpress")
+	if manifest.Version != "1.0.0" {
+		t.Errorf("Expected catalog's wordpress 1.0.0 after removing the overlay but actual is %s", manifest.Version)
+	}
+}
+
+func TestAddLocalApp(t *testing.T) {
+	restoreHome := fixtures.UseTemporaryHome(t)
+	defer restoreHome()
+
+	dir := fixtures.WriteFiles(t, map[string]string{
+		"my-app/manifest.yaml":   "namespace: my-app\nversion: 0.1.0\ncategory: management\ndependencies:\n- mariadb\n",
+		"my-app/post_install.md": "# My app\n",
+		"my-app/install.sh":      "#!/bin/bash\n",
+	})
+	defer os.RemoveAll(dir)
+	appDirPath := filepath.Join(dir, "my-app")
+
+	issues, _ := LintApp(appDirPath, map[string]AppManifest{})
+	if !HasLintErrors(issues) {
+		t.Errorf("Expected missing mariadb dependency to be an error")
+	}
+
+	issues, _ = LintApp(appDirPath, map[string]AppManifest{"mariadb": {Version: "10.5"}})
+	if len(issues) != 0 {
+		t.Errorf("Expected no issues but got %+v", issues)
+	}
+
+	appName, err := AddLocalApp(appDirPath)
+	if err != nil || appName != "my-app" {
+		t.Fatalf("Expected my-app but got %s (%v)", appName, err)
+	}
+	defer delete(localApps, appName)
+
+	if !IsAppExist("my-app") {
+		t.Errorf("Expected my-app to exist")
+	}
+
+	isOverlayApp, _ := IsOverlayApp("my-app")
+	if !isOverlayApp {
+		t.Errorf("Expected my-app to be validated like an overlay app")
+	}
+}
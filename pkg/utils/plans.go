@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PlanSelectorSmallest picks the smallest plan of an app
+	PlanSelectorSmallest = "smallest"
+	// PlanSelectorLargest picks the largest plan of an app
+	PlanSelectorLargest = "largest"
+)
+
+// planSizeRegex matches sizes like "5Gi", "500GB", "1.5 TB" or "3"
+var planSizeRegex = regexp.MustCompile(`(?i)^\s*([0-9]+(?:\.[0-9]+)?)\s*(([kmgtp])(i)?)?b?\s*$`)
+
+// planSizeUnits are the exponents of the unit prefixes
+var planSizeUnits = map[string]float64{"k": 1, "m": 2, "g": 3, "t": 4, "p": 5}
+
+// ParsePlanSize returns the size in bytes of a plan label or value e.g. "5Gi" or
+// "500GB". Binary (Mi, Gi, Ti) and decimal (MB, GB, TB) units are supported, a
+// number without unit is returned as is. It returns false when it's not a size.
+func ParsePlanSize(plan string) (float64, bool) {
+	matches := planSizeRegex.FindStringSubmatch(plan)
+	if matches == nil {
+		return 0, false
+	}
+
+	size, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if matches[3] != "" {
+		base := 1000.0
+		if matches[4] != "" {
+			base = 1024.0
+		}
+
+		exponent := planSizeUnits[strings.ToLower(matches[3])]
+		for i := 0.0; i < exponent; i++ {
+			size *= base
+		}
+	}
+
+	return size, true
+}
+
+// sortPlans sorts the plans by size, using sizeOf. Plans without size keep their
+// order and come after the ones with a size.
+func sortPlans(plans []string, sizeOf func(plan string) (float64, bool)) []string {
+	sorted := append([]string{}, plans...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		sizeI, okI := sizeOf(sorted[i])
+		sizeJ, okJ := sizeOf(sorted[j])
+		if okI && okJ {
+			return sizeI < sizeJ
+		}
+		return okI && !okJ
+	})
+	return sorted
+}
+
+// SortAppPlans returns the plan labels of the manifest from the smallest to the largest.
+// The label is used as size when possible e.g. "5GB", otherwise the plan's value.
+func SortAppPlans(manifest AppManifest) []string {
+	labels := []string{}
+	values := make(map[string]string)
+	for _, plan := range manifest.Plans {
+		labels = append(labels, plan.Label)
+	}
+
+	// same key as GetAppPlanVariableName
+	planKey := planVariableName(manifest)
+	if planKey != "" {
+		for _, plan := range manifest.Plans {
+			values[plan.Label] = plan.Configuration[planKey].Value
+		}
+	}
+
+	return sortPlans(labels, func(label string) (float64, bool) {
+		size, ok := ParsePlanSize(label)
+		if ok {
+			return size, true
+		}
+		return ParsePlanSize(values[label])
+	})
+}
+
+// planVariableName returns the configuration key the plans are about, or an empty
+// string when the plans have no configuration. Only the keys declared by every plan
+// are candidates, and a key whose values are all sizes with a unit (e.g. "5Gi") is
+// preferred over plain numbers like a count of replicas. The first one by name is
+// picked among the remaining candidates.
+func planVariableName(manifest AppManifest) string {
+	counts := make(map[string]int)
+	for _, plan := range manifest.Plans {
+		for key := range plan.Configuration {
+			counts[key]++
+		}
+	}
+
+	candidates := []string{}
+	for key, count := range counts {
+		if count == len(manifest.Plans) {
+			candidates = append(candidates, key)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+
+	for _, key := range candidates {
+		sized := true
+		for _, plan := range manifest.Plans {
+			matches := planSizeRegex.FindStringSubmatch(plan.Configuration[key].Value)
+			if matches == nil || matches[2] == "" {
+				sized = false
+				break
+			}
+		}
+
+		if sized {
+			return key
+		}
+	}
+
+	return candidates[0]
+}
+
+// GetLargestAppPlan is like GetSmallestAppPlan but returns the largest plan, or
+// the last one when the labels are not sizes
+func GetLargestAppPlan(plans []string) string {
+	sorted := sortPlans(plans, ParsePlanSize)
+
+	// plans without size are at the end, so the largest size is before them
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if _, ok := ParsePlanSize(sorted[i]); ok {
+			return sorted[i]
+		}
+	}
+	return sorted[len(sorted)-1]
+}
+
+// SelectAppPlan returns the plan label of the app matching the selector i.e. smallest,
+// largest or a plan label. It returns an empty label when the app has no plans.
+func SelectAppPlan(appName, selector string) (string, error) {
+	planLabels, err := GetAppPlans(appName)
+	if err != nil {
+		return "", fmt.Errorf("unable to list app's plans - %v", err)
+	}
+
+	if len(planLabels) == 0 {
+		if selector == PlanSelectorSmallest || selector == PlanSelectorLargest {
+			return "", nil
+		}
+		return "", fmt.Errorf("%s app does not have any plans", appName)
+	}
+
+	switch selector {
+	case PlanSelectorSmallest:
+		return GetSmallestAppPlan(planLabels), nil
+	case PlanSelectorLargest:
+		return GetLargestAppPlan(planLabels), nil
+	}
+
+	if !containsString(planLabels, selector) {
+		return "", fmt.Errorf("the given plan is not supported for %s app - supported values are %v", appName, strings.Join(planLabels, ", "))
+	}
+	return selector, nil
+}
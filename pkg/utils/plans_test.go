@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestParsePlanSize(t *testing.T) {
+	cases := map[string]float64{
+		"5Gi":    5 * 1024 * 1024 * 1024,
+		"5GiB":   5 * 1024 * 1024 * 1024,
+		"500MB":  500 * 1000 * 1000,
+		"1 TB":   1000 * 1000 * 1000 * 1000,
+		"1.5Ti":  1.5 * 1024 * 1024 * 1024 * 1024,
+		"512mi":  512 * 1024 * 1024,
+		"3":      3,
+		"100 B":  100,
+		"2Kib":   2048,
+		"1.5 Gb": 1.5 * 1000 * 1000 * 1000,
+	}
+
+	for plan, expected := range cases {
+		actual, ok := ParsePlanSize(plan)
+		if !ok || actual != expected {
+			t.Errorf("Expected %s to be %v bytes but got %v (%v)", plan, expected, actual, ok)
+		}
+	}
+
+	for _, plan := range []string{"Linkerd Minimal", "five Gi", "5 nodes", ""} {
+		if _, ok := ParsePlanSize(plan); ok {
+			t.Errorf("Expected %q not to be a size", plan)
+		}
+	}
+}
+
+func TestGetSmallestAndLargestAppPlan(t *testing.T) {
+	plans := []string{"1TB", "500GB", "20Gi", "Custom"}
+	if actual := GetSmallestAppPlan(plans); actual != "20Gi" {
+		t.Errorf("Expected 20Gi to be the smallest plan but got %s", actual)
+	}
+	if actual := GetLargestAppPlan(plans); actual != "1TB" {
+		t.Errorf("Expected 1TB to be the largest plan but got %s", actual)
+	}
+
+	plans = []string{"Minimal", "Full"}
+	if actual := GetSmallestAppPlan(plans); actual != "Minimal" {
+		t.Errorf("Expected the first plan to be the smallest but got %s", actual)
+	}
+	if actual := GetLargestAppPlan(plans); actual != "Full" {
+		t.Errorf("Expected the last plan to be the largest but got %s", actual)
+	}
+}
+
+func TestGetAppPlansSorted(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"mariadb/manifest.yaml": "plans:\n- label: 1TB\n- label: 500GB\n- label: 10GB\n",
+		"minio/manifest.yaml":   "plans:\n- label: Large\n  configuration:\n    VOLUME_SIZE:\n      value: 1Ti\n- label: Small\n  configuration:\n    VOLUME_SIZE:\n      value: 500Gi\n",
+	})
+	defer cleanup()
+
+	plans, _ := GetAppPlans("mariadb")
+	if strings.Join(plans, ",") != "10GB,500GB,1TB" {
+		t.Errorf("Expected plans sorted by label size but got %v", plans)
+	}
+
+	plans, _ = GetAppPlans("minio")
+	if strings.Join(plans, ",") != "Small,Large" {
+		t.Errorf("Expected plans sorted by value size but got %v", plans)
+	}
+}
+
+func TestGetAppPlanVariableNameMultipleKeys(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"redis/manifest.yaml": multiValuesManifest,
+		"kafka/manifest.yaml": "plans:\n- label: small\n  configuration:\n    BROKERS:\n      value: \"1\"\n    AUTH_TOKEN:\n      value: secret\n- label: large\n  configuration:\n    BROKERS:\n      value: \"3\"\n",
+	})
+	defer cleanup()
+
+	// REPLICAS comes first by name, but VOLUME_SIZE is the size of the plan
+	planKey, err := GetAppPlanVariableName("redis")
+	if err != nil || planKey != "VOLUME_SIZE" {
+		t.Errorf("Expected VOLUME_SIZE to be the plan variable but got %s (%v)", planKey, err)
+	}
+
+	// AUTH_TOKEN is not declared by every plan
+	planKey, err = GetAppPlanVariableName("kafka")
+	if err != nil || planKey != "BROKERS" {
+		t.Errorf("Expected BROKERS to be the plan variable but got %s (%v)", planKey, err)
+	}
+}
+
+func TestSelectAppPlan(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"mariadb/manifest.yaml": "plans:\n- label: 1TB\n- label: 500GB\n",
+		"linkerd/manifest.yaml": "version: 2.10\n",
+	})
+	defer cleanup()
+
+	cases := map[string]string{"smallest": "500GB", "largest": "1TB", "1TB": "1TB"}
+	for selector, expected := range cases {
+		actual, err := SelectAppPlan("mariadb", selector)
+		if err != nil || actual != expected {
+			t.Errorf("Expected %s plan for %s but got %s (%v)", expected, selector, actual, err)
+		}
+	}
+
+	_, err := SelectAppPlan("mariadb", "2TB")
+	if err == nil {
+		t.Error("Expected an error for an unknown plan")
+	}
+
+	actual, err := SelectAppPlan("linkerd", "largest")
+	if err != nil || actual != "" {
+		t.Errorf("Expected no plan for an app without plans but got %s (%v)", actual, err)
+	}
+}
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AppToInstall is an app with the plan label to install it with
+type AppToInstall struct {
+	// Name is the app reference i.e. [SOURCE/]APP_NAME
+	Name      string
+	PlanLabel string
+	// RequiredBy is empty when the app is requested by the user,
+	// otherwise it's the apps that depend on it
+	RequiredBy []string
+	// DependsOn are the names of the apps it depends on, requested or not
+	DependsOn []string
+	// Values override the configuration of the plan, keyed by variable name
+	Values map[string]string
+	// InstanceName is the name of the App in the cluster, the app name when empty
+	InstanceName string
+}
+
+// ResolveDependencies expands the dependencies of the requested apps recursively and
+// returns all apps in the order they must be created i.e. dependencies first. Dependencies
+// for which isInstalled returns true are skipped (along with their own dependencies) and
+// returned separately. An error is returned on circular or unknown dependencies.
+func ResolveDependencies(requested []AppToInstall, isInstalled func(appName string) bool) ([]AppToInstall, []string, error) {
+	r := &dependencyResolver{
+		requested:   make(map[string]bool),
+		planLabels:  make(map[string]string),
+		requiredBy:  make(map[string][]string),
+		dependsOn:   make(map[string][]string),
+		appRefs:     make(map[string]string),
+		options:     make(map[string]AppToInstall),
+		done:        make(map[string]bool),
+		skipped:     make(map[string]bool),
+		isInstalled: isInstalled,
+	}
+
+	for _, app := range requested {
+		_, name := ParseAppRef(app.Name)
+		r.requested[name] = true
+		r.planLabels[name] = app.PlanLabel
+		r.appRefs[name] = app.Name
+		r.options[name] = app
+	}
+
+	for _, app := range requested {
+		err := r.visit(app.Name, []string{})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ordered := []AppToInstall{}
+	for _, name := range r.order {
+		ordered = append(ordered, AppToInstall{
+			Name:         r.appRefs[name],
+			PlanLabel:    r.planLabels[name],
+			RequiredBy:   r.requiredBy[name],
+			DependsOn:    r.dependsOn[name],
+			Values:       r.options[name].Values,
+			InstanceName: r.options[name].InstanceName,
+		})
+	}
+
+	return ordered, r.skippedOrder, nil
+}
+
+type dependencyResolver struct {
+	// requested apps (by name), their plan can't be changed by dependencies
+	requested map[string]bool
+	// planLabels and appRefs are keyed by app name
+	planLabels map[string]string
+	requiredBy map[string][]string
+	dependsOn  map[string][]string
+	appRefs    map[string]string
+	// options are the requested apps as given, for their values, instance name and namespace
+	options      map[string]AppToInstall
+	done         map[string]bool
+	skipped      map[string]bool
+	order        []string
+	skippedOrder []string
+	isInstalled  func(appName string) bool
+}
+
+// visit adds the dependencies of the app to the order, then the app itself.
+// The path holds the apps above in the dependency tree.
+func (r *dependencyResolver) visit(appRef string, path []string) error {
+	catalogName, name := ParseAppRef(appRef)
+	for i, appName := range path {
+		if appName == name {
+			cycle := append(append([]string{}, path[i:]...), name)
+			return fmt.Errorf("circular dependency %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if r.done[name] || r.skipped[name] {
+		return nil
+	}
+
+	manifest, err := GetAppManifest(appRef)
+	if err != nil {
+		return fmt.Errorf("unable to load %s app manifest - %v", appRef, err)
+	}
+
+	for _, dependency := range manifest.Dependencies {
+		dependencyName, planLabel := ParseDependency(dependency)
+		r.dependsOn[name] = append(r.dependsOn[name], dependencyName)
+
+		// dependencies are looked up in the dependent's catalog first
+		dependencyRef := dependencyName
+		if catalogName != "" && IsAppExist(fmt.Sprintf("%s/%s", catalogName, dependencyName)) {
+			dependencyRef = fmt.Sprintf("%s/%s", catalogName, dependencyName)
+		}
+
+		if !IsAppExist(dependencyRef) {
+			return fmt.Errorf("%s app depends on %s app which can't be found", name, dependencyName)
+		}
+
+		if !r.requested[dependencyName] && !r.done[dependencyName] && !r.skipped[dependencyName] && r.isInstalled(dependencyName) {
+			r.skipped[dependencyName] = true
+			r.skippedOrder = append(r.skippedOrder, dependencyName)
+			continue
+		}
+
+		err = r.setDependencyPlan(name, dependencyRef, planLabel)
+		if err != nil {
+			return err
+		}
+
+		err = r.visit(dependencyRef, append(path, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	r.done[name] = true
+	r.order = append(r.order, name)
+	return nil
+}
+
+// setDependencyPlan records the plan the dependency is required with. When the dependency
+// has plans but none is given, the smallest one is used.
+func (r *dependencyResolver) setDependencyPlan(dependent, dependencyRef, planLabel string) error {
+	_, dependencyName := ParseAppRef(dependencyRef)
+
+	// the user's choice wins
+	if r.requested[dependencyName] {
+		return nil
+	}
+
+	if _, found := r.appRefs[dependencyName]; !found {
+		r.appRefs[dependencyName] = dependencyRef
+	}
+	r.requiredBy[dependencyName] = append(r.requiredBy[dependencyName], dependent)
+
+	planLabels, err := GetAppPlans(dependencyRef)
+	if err != nil {
+		return fmt.Errorf("unable to list %s app's plans - %v", dependencyName, err)
+	}
+
+	if planLabel == "" && len(planLabels) > 0 {
+		planLabel = GetSmallestAppPlan(planLabels)
+	}
+
+	if planLabel != "" && !containsString(planLabels, planLabel) {
+		return fmt.Errorf("%s app depends on %s plan of %s app which does not exist - supported values are %v", dependent, planLabel, dependencyName, strings.Join(planLabels, ", "))
+	}
+
+	current, found := r.planLabels[dependencyName]
+	if found && current != planLabel {
+		return fmt.Errorf("%s app is required with %s plan and %s plan - please install it first with the plan of your choice", dependencyName, current, planLabel)
+	}
+
+	r.planLabels[dependencyName] = planLabel
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
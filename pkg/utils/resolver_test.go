@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestResolveDependencies(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"wordpress/manifest.yaml": "dependencies:\n- MariaDB:10GB\n- longhorn\n",
+		"mariadb/manifest.yaml":   "dependencies:\n- longhorn\nplans:\n- label: 5GB\n  configuration:\n    VOLUME_SIZE:\n      value: 5Gi\n- label: 10GB\n  configuration:\n    VOLUME_SIZE:\n      value: 10Gi\n",
+		"longhorn/manifest.yaml":  "version: 1.0.0\n",
+		"linkerd/manifest.yaml":   "version: 2.10\n",
+	})
+	defer cleanup()
+
+	requested := []AppToInstall{{Name: "wordpress"}, {Name: "linkerd"}}
+	apps, skipped, err := ResolveDependencies(requested, func(appName string) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []AppToInstall{
+		{Name: "longhorn", RequiredBy: []string{"mariadb", "wordpress"}},
+		{Name: "mariadb", PlanLabel: "10GB", RequiredBy: []string{"wordpress"}, DependsOn: []string{"longhorn"}},
+		{Name: "wordpress", DependsOn: []string{"mariadb", "longhorn"}},
+		{Name: "linkerd"},
+	}
+	if len(apps) != len(expected) {
+		t.Fatalf("Expected %+v but actual is %+v", expected, apps)
+	}
+	for i := range expected {
+		if apps[i].Name != expected[i].Name || apps[i].PlanLabel != expected[i].PlanLabel || strings.Join(apps[i].RequiredBy, ",") != strings.Join(expected[i].RequiredBy, ",") || strings.Join(apps[i].DependsOn, ",") != strings.Join(expected[i].DependsOn, ",") {
+			t.Errorf("Expected %+v but actual is %+v", expected[i], apps[i])
+		}
+	}
+
+	if len(skipped) != 0 {
+		t.Errorf("Expected nothing to be skipped but got %v", skipped)
+	}
+}
+
+func TestResolveDependenciesSkipsInstalled(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"wordpress/manifest.yaml": "dependencies:\n- mariadb\n",
+		"mariadb/manifest.yaml":   "dependencies:\n- longhorn\n",
+		"longhorn/manifest.yaml":  "version: 1.0.0\n",
+	})
+	defer cleanup()
+
+	apps, skipped, err := ResolveDependencies([]AppToInstall{{Name: "wordpress"}}, func(appName string) bool {
+		return appName == "mariadb"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(apps) != 1 || apps[0].Name != "wordpress" {
+		t.Errorf("Expected only wordpress but actual is %+v", apps)
+	}
+
+	if !elementsMatch(skipped, []string{"mariadb"}) {
+		t.Errorf("Expected mariadb to be skipped but got %v", skipped)
+	}
+}
+
+func TestResolveDependenciesCycle(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"a/manifest.yaml": "dependencies:\n- b\n",
+		"b/manifest.yaml": "dependencies:\n- c\n",
+		"c/manifest.yaml": "dependencies:\n- a\n",
+	})
+	defer cleanup()
+
+	_, _, err := ResolveDependencies([]AppToInstall{{Name: "a"}}, func(appName string) bool { return false })
+	if err == nil || !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Errorf("Expected circular dependency error but got %v", err)
+	}
+}
+
+func TestResolveDependenciesUnknownPlan(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"wordpress/manifest.yaml": "dependencies:\n- mariadb:50GB\n",
+		"mariadb/manifest.yaml":   "plans:\n- label: 5GB\n  configuration:\n    VOLUME_SIZE:\n      value: 5Gi\n",
+	})
+	defer cleanup()
+
+	_, _, err := ResolveDependencies([]AppToInstall{{Name: "wordpress"}}, func(appName string) bool { return false })
+	if err == nil {
+		t.Errorf("Expected an error for unknown plan of the dependency")
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"unicode"
+)
+
+// FuzzyMatch checks if all characters of query appear in text, in order and
+// case-insensitively. It returns a score (0 means no match, higher is better) and
+// the positions (rune indexes) of the matched characters in text. A substring
+// scores higher than scattered characters, and a prefix higher than a substring.
+func FuzzyMatch(query, text string) (int, []int) {
+	q := []rune(toLower(query))
+	t := []rune(toLower(text))
+
+	if len(q) == 0 || len(q) > len(t) {
+		return 0, nil
+	}
+
+	// substring match
+	for i := 0; i+len(q) <= len(t); i++ {
+		if string(t[i:i+len(q)]) != string(q) {
+			continue
+		}
+
+		score := 100 + 2*len(q)
+		if i == 0 {
+			score += 50
+		}
+		if len(q) == len(t) {
+			score += 100
+		}
+
+		positions := []int{}
+		for j := i; j < i+len(q); j++ {
+			positions = append(positions, j)
+		}
+		return score, positions
+	}
+
+	// scattered characters, consecutive ones are rewarded and gaps are penalized
+	score := 50
+	positions := []int{}
+	qi := 0
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		if len(positions) > 0 {
+			gap := ti - positions[len(positions)-1] - 1
+			if gap == 0 {
+				score += 5
+			} else {
+				score -= gap
+			}
+		}
+
+		positions = append(positions, ti)
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, nil
+	}
+
+	if score < 1 {
+		score = 1
+	}
+	return score, positions
+}
+
+// toLower lowercases rune by rune, so rune indexes still point to the same
+// characters (strings.ToLower may change the length of some strings)
+func toLower(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatchSubstring(t *testing.T) {
+	score, positions := FuzzyMatch("press", "WordPress")
+	if score == 0 {
+		t.Errorf("Expected a match")
+	}
+
+	expected := []int{4, 5, 6, 7, 8}
+	if !reflect.DeepEqual(expected, positions) {
+		t.Errorf("Expected %v but actual is %v", expected, positions)
+	}
+}
+
+func TestFuzzyMatchScattered(t *testing.T) {
+	score, positions := FuzzyMatch("wdp", "wordpress")
+	if score == 0 {
+		t.Errorf("Expected a match")
+	}
+
+	expected := []int{0, 3, 4}
+	if !reflect.DeepEqual(expected, positions) {
+		t.Errorf("Expected %v but actual is %v", expected, positions)
+	}
+}
+
+func TestFuzzyMatchNoMatch(t *testing.T) {
+	score, positions := FuzzyMatch("redis", "rabbitmq")
+	if score != 0 || positions != nil {
+		t.Errorf("Expected no match but got score %d at %v", score, positions)
+	}
+}
+
+func TestFuzzyMatchRanking(t *testing.T) {
+	exact, _ := FuzzyMatch("redis", "redis")
+	prefix, _ := FuzzyMatch("redis", "redis-cluster")
+	substring, _ := FuzzyMatch("redis", "bitnami-redis")
+
+	if !(exact > prefix && prefix > substring) {
+		t.Errorf("Expected exact (%d) > prefix (%d) > substring (%d)", exact, prefix, substring)
+	}
+
+	scattered, _ := FuzzyMatch("rds", "redis")
+	if !(substring > scattered) {
+		t.Errorf("Expected substring (%d) > scattered (%d)", substring, scattered)
+	}
+}
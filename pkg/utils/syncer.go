@@ -0,0 +1,291 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// CatalogSyncer knows how to download and refresh a catalog (Git repository)
+// on disk. It can be replaced e.g. in tests, see SetCatalogSyncer.
+type CatalogSyncer interface {
+	// Clone downloads the repository at the given branch or tag (ref) into directory.
+	// When ref is empty, the remote's default branch is used.
+	Clone(url, ref, directory string) (string, error)
+	// Pull fast-forwards the branch (ref) checked out in directory.
+	// When ref is empty, the checked out branch is used.
+	Pull(directory, ref string) (string, error)
+	// LatestCommitHash returns the short hash of the commit checked out in directory
+	LatestCommitHash(directory string) (string, error)
+	// Checkout detaches HEAD at the given commit (full or abbreviated hash)
+	Checkout(directory, commit string) error
+	// ReadFiles returns the content of fileName inside every top-level folder
+	// at the given commit (full or abbreviated hash), keyed by folder name
+	ReadFiles(directory, commit, fileName string) (map[string][]byte, error)
+	// CommitBefore returns the short hash of the last commit made at or before the
+	// given time. It's empty when all commits are newer.
+	CommitBefore(directory string, before time.Time) (string, error)
+}
+
+// GoGitSyncer is the default CatalogSyncer. It does not need the git program.
+type GoGitSyncer struct{}
+
+var catalogSyncer CatalogSyncer = &GoGitSyncer{}
+
+// GetCatalogSyncer returns the CatalogSyncer used by GitClone, GitPull, GitLatestCommitHash and GitCheckout
+func GetCatalogSyncer() CatalogSyncer {
+	return catalogSyncer
+}
+
+// SetCatalogSyncer replaces the CatalogSyncer used by GitClone, GitPull, GitLatestCommitHash and GitCheckout
+func SetCatalogSyncer(syncer CatalogSyncer) {
+	catalogSyncer = syncer
+}
+
+// Clone implements CatalogSyncer. The ref is looked up as a branch first, then as a tag.
+func (s *GoGitSyncer) Clone(url, ref, directory string) (string, error) {
+	var progress bytes.Buffer
+	directory = path.Clean(directory)
+
+	if ref == "" {
+		_, err := git.PlainClone(directory, false, &git.CloneOptions{
+			URL:      url,
+			Progress: &progress,
+		})
+		return progress.String(), err
+	}
+
+	_, err := git.PlainClone(directory, false, &git.CloneOptions{
+		URL:           url,
+		ReferenceName: plumbing.NewBranchReferenceName(ref),
+		SingleBranch:  true,
+		Progress:      &progress,
+	})
+	if err == nil {
+		return progress.String(), nil
+	}
+
+	// PlainClone leaves the directory behind on failure
+	_ = os.RemoveAll(directory)
+	DebugPrintf("Unable to clone %s as a branch (%v), trying as a tag\n", ref, err)
+
+	_, err = git.PlainClone(directory, false, &git.CloneOptions{
+		URL:           url,
+		ReferenceName: plumbing.NewTagReferenceName(ref),
+		SingleBranch:  true,
+		Progress:      &progress,
+	})
+	if err != nil {
+		_ = os.RemoveAll(directory)
+		return "", fmt.Errorf("unable to find %s branch or tag - %v", ref, err)
+	}
+
+	return progress.String(), nil
+}
+
+// Pull implements CatalogSyncer. Repositories cloned from a tag
+// have nothing to pull, so they are left as they are.
+func (s *GoGitSyncer) Pull(directory, ref string) (string, error) {
+	var progress bytes.Buffer
+
+	repo, err := git.PlainOpen(path.Clean(directory))
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	if !head.Name().IsBranch() {
+		return fmt.Sprintf("HEAD is detached at %s, nothing to pull", head.Hash().String()[:7]), nil
+	}
+
+	referenceName := head.Name()
+	if ref != "" {
+		referenceName = plumbing.NewBranchReferenceName(ref)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	err = worktree.Pull(&git.PullOptions{
+		RemoteName:    "origin",
+		ReferenceName: referenceName,
+		SingleBranch:  true,
+		Progress:      &progress,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return "Already up to date.", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return progress.String(), nil
+}
+
+// LatestCommitHash implements CatalogSyncer
+func (s *GoGitSyncer) LatestCommitHash(directory string) (string, error) {
+	repo, err := git.PlainOpen(path.Clean(directory))
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String()[:7], nil
+}
+
+// Checkout implements CatalogSyncer
+func (s *GoGitSyncer) Checkout(directory, commit string) error {
+	repo, err := git.PlainOpen(path.Clean(directory))
+	if err != nil {
+		return err
+	}
+
+	hash, err := findCommitHash(repo, commit)
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	return worktree.Checkout(&git.CheckoutOptions{
+		Hash:  hash,
+		Force: true,
+	})
+}
+
+// ReadFiles implements CatalogSyncer
+func (s *GoGitSyncer) ReadFiles(directory, commit, fileName string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	repo, err := git.PlainOpen(path.Clean(directory))
+	if err != nil {
+		return files, err
+	}
+
+	hash, err := findCommitHash(repo, commit)
+	if err != nil {
+		return files, err
+	}
+
+	commitObject, err := repo.CommitObject(hash)
+	if err != nil {
+		return files, err
+	}
+
+	tree, err := commitObject.Tree()
+	if err != nil {
+		return files, err
+	}
+
+	for _, entry := range tree.Entries {
+		if entry.Mode != filemode.Dir {
+			continue
+		}
+
+		file, err := tree.File(path.Join(entry.Name, fileName))
+		if err == object.ErrFileNotFound {
+			continue
+		}
+		if err != nil {
+			return files, err
+		}
+
+		content, err := file.Contents()
+		if err != nil {
+			return files, err
+		}
+		files[entry.Name] = []byte(content)
+	}
+
+	return files, nil
+}
+
+// CommitBefore implements CatalogSyncer
+func (s *GoGitSyncer) CommitBefore(directory string, before time.Time) (string, error) {
+	repo, err := git.PlainOpen(path.Clean(directory))
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	commits, err := repo.Log(&git.LogOptions{
+		From:  head.Hash(),
+		Order: git.LogOrderCommitterTime,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	found := ""
+	err = commits.ForEach(func(c *object.Commit) error {
+		if c.Committer.When.After(before) {
+			return nil
+		}
+		found = c.Hash.String()[:7]
+		return storer.ErrStop
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return found, nil
+}
+
+// findCommitHash returns the full hash of the commit whose hash starts with prefix
+func findCommitHash(repo *git.Repository, prefix string) (plumbing.Hash, error) {
+	prefix = strings.ToLower(prefix)
+	if len(prefix) < 4 {
+		return plumbing.ZeroHash, fmt.Errorf("commit %s is too short - please use at least 4 characters", prefix)
+	}
+
+	commits, err := repo.CommitObjects()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	matches := []plumbing.Hash{}
+	err = commits.ForEach(func(c *object.Commit) error {
+		if strings.HasPrefix(c.Hash.String(), prefix) {
+			matches = append(matches, c.Hash)
+		}
+		return nil
+	})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return plumbing.ZeroHash, fmt.Errorf("commit %s not found", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return plumbing.ZeroHash, fmt.Errorf("commit %s is ambiguous", prefix)
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+func TestGoGitSyncerCloneAndPull(t *testing.T) {
+	sourceDir, expectedHash := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+
+	targetDir, _ := ioutil.TempDir("", "kubemart-clone")
+	defer os.RemoveAll(targetDir)
+	targetDir = filepath.Join(targetDir, "apps")
+
+	syncer := &GoGitSyncer{}
+	_, err := syncer.Clone(sourceDir, "", targetDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualHash, _ := syncer.LatestCommitHash(targetDir)
+	if expectedHash != actualHash {
+		t.Errorf("Expected %s but actual is %s", expectedHash, actualHash)
+	}
+
+	_, err = syncer.Pull(targetDir, "")
+	if err != nil {
+		t.Errorf("Expected no error when already up to date but got %v", err)
+	}
+}
+
+func TestGoGitSyncerCloneUnknownRef(t *testing.T) {
+	sourceDir, _ := fixtures.NewLocalRepository(t)
+	defer os.RemoveAll(sourceDir)
+
+	targetDir, _ := ioutil.TempDir("", "kubemart-clone")
+	defer os.RemoveAll(targetDir)
+	targetDir = filepath.Join(targetDir, "apps")
+
+	syncer := &GoGitSyncer{}
+	_, err := syncer.Clone(sourceDir, "not-a-branch", targetDir)
+	if err == nil {
+		t.Errorf("Expected an error when cloning unknown ref")
+	}
+
+	if _, err := os.Stat(targetDir); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed after failed clone", targetDir)
+	}
+}
@@ -10,9 +10,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
-	"path"
 	"path/filepath"
-	"reflect"
 	"regexp"
 	"runtime"
 	"strconv"
@@ -61,13 +59,20 @@ type AppManifest struct {
 			Value string `yaml:"value"`
 		} `yaml:"configuration"`
 	} `yaml:"plans"`
-	Version  string `yaml:"version"`
-	Category string `yaml:"category"`
+	Version     string `yaml:"version"`
+	Category    string `yaml:"category"`
+	Description string `yaml:"description"`
 }
 
 // KubemartConfigFile is the structure of ~/.kubemart/config.json file
 type KubemartConfigFile struct {
-	AppsLastUpdatedAt int64 `json:"apps_last_updated_at"`
+	AppsLastUpdatedAt int64           `json:"apps_last_updated_at"`
+	Catalogs          []CatalogSource `json:"catalogs"`
+	Offline           bool            `json:"offline"`
+	// PreviousCommits is the commit each catalog was at before the last refresh
+	PreviousCommits map[string]string `json:"previous_commits,omitempty"`
+	// Overlays are directories of apps (in development) that take precedence over the catalogs
+	Overlays []string `json:"overlays,omitempty"`
 }
 
 // KubemartConfigMap is used when saving ConfigMap
@@ -80,9 +85,10 @@ type KubemartConfigMap struct {
 
 // KubemartPaths contains all important paths for kubemart operation
 type KubemartPaths struct {
-	RootDirectoryPath string
-	AppsDirectoryPath string
-	ConfigFilePath    string
+	RootDirectoryPath     string
+	AppsDirectoryPath     string
+	CatalogsDirectoryPath string
+	ConfigFilePath        string
 }
 
 // LatestGitHubReleaseResponse is the structure of GitHub API response.
@@ -103,6 +109,7 @@ func GetKubemartPaths() (*KubemartPaths, error) {
 	kubemartDirPath := filepath.Join(homeDir, ".kubemart")
 	bp.RootDirectoryPath = kubemartDirPath
 	bp.AppsDirectoryPath = filepath.Join(kubemartDirPath, "apps")
+	bp.CatalogsDirectoryPath = filepath.Join(kubemartDirPath, "catalogs")
 	bp.ConfigFilePath = filepath.Join(kubemartDirPath, "config.json")
 
 	return bp, nil
@@ -283,73 +290,29 @@ func IsCommandAvailable(name string) bool {
 	return err == nil
 }
 
-// GitClone will run `git clone` and save the contents into target directory
-func GitClone(directory string) (string, error) {
-	var stdErrBuf bytes.Buffer
-	url := fmt.Sprintf("https://github.com/%s/kubernetes-marketplace.git", marketplaceAccount)
-
-	args := []string{
-		"clone",
-		"--branch",
-		marketplaceBranch,
-		"--progress",
-		url,
-		path.Clean(directory),
-	}
-	DebugPrintf("git command args - %v\n", args)
-
-	cmd := exec.Command("git", args...)
-	cmd.Stderr = &stdErrBuf // because `git clone` use stderr
-	err := cmd.Run()
-	if err != nil {
-		return "", err
-	}
-
-	cloneOutput := stdErrBuf.String()
-	return cloneOutput, nil
+// GitClone will clone the repository (url) at the given branch or tag (ref)
+// into target directory. When ref is empty, the remote's default branch is cloned.
+func GitClone(url, ref, directory string) (string, error) {
+	DebugPrintf("Cloning %s (ref: %q) into %s\n", url, ref, directory)
+	return GetCatalogSyncer().Clone(url, ref, directory)
 }
 
 // UpdateConfigFileLastUpdatedTimestamp will update timestamp field of ~/.kubemart/config.json file
 func UpdateConfigFileLastUpdatedTimestamp() error {
-	bp, err := GetKubemartPaths()
-	if err != nil {
-		return err
-	}
-
-	configFilePath := bp.ConfigFilePath
-	config := &KubemartConfigFile{
-		AppsLastUpdatedAt: time.Now().Unix(),
-	}
-
-	file, err := json.MarshalIndent(config, "", " ")
+	config, err := ReadConfigFile()
 	if err != nil {
 		return err
 	}
 
-	err = ioutil.WriteFile(configFilePath, file, 0644)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	config.AppsLastUpdatedAt = time.Now().Unix()
+	return WriteConfigFile(config)
 }
 
-// GitPull will run `git pull` in the context of given directory
-func GitPull(directory string) (string, error) {
-	args := []string{
-		"-C",
-		path.Clean(directory),
-		"pull",
-		"origin",
-		marketplaceBranch,
-	}
-	DebugPrintf("git command args - %v\n", args)
-
-	out, err := exec.Command("git", args...).Output()
-	if err != nil {
-		return "", err
-	}
-	return string(out), nil
+// GitPull will pull the latest commits in the context of given directory.
+// When ref is empty, the checked out branch is pulled.
+func GitPull(directory, ref string) (string, error) {
+	DebugPrintf("Pulling %s (ref: %q)\n", directory, ref)
+	return GetCatalogSyncer().Pull(directory, ref)
 }
 
 // GetConfigFileLastUpdatedTimestamp is used to read timestamp field from ~/.kubemart/config.json file
@@ -373,9 +336,15 @@ func GetConfigFileLastUpdatedTimestamp() int64 {
 	return config.AppsLastUpdatedAt
 }
 
-// UpdateAppsCacheIfStale will run `git pull` in the context of ~/.kubemart/apps folder
-// and update the timestamp field in the ~/.kubemart/config.json file
+// UpdateAppsCacheIfStale will run `git pull` in the context of every catalog folder
+// (catalogs that were never cloned get cloned) and update the timestamp field
+// in the ~/.kubemart/config.json file
 func UpdateAppsCacheIfStale() (bool, error) {
+	if IsOfflineMode() {
+		DebugPrintf("Offline mode is enabled. Git pull is skipped.\n")
+		return true, nil
+	}
+
 	lastUpdated := GetConfigFileLastUpdatedTimestamp()
 	now := time.Now().Unix()
 	diff := now - lastUpdated
@@ -388,20 +357,69 @@ func UpdateAppsCacheIfStale() (bool, error) {
 	}
 
 	// when the apps are outdated
-	bp, err := GetKubemartPaths()
+	sources, err := GetCatalogSources()
 	if err != nil {
-		return false, fmt.Errorf("unable to load kubemart paths - %v", err)
+		return false, fmt.Errorf("unable to load catalogs - %v\nThe 'kubemart catalog repair' command may solve this problem", err)
 	}
 
-	appsFolder := bp.AppsDirectoryPath
-	DebugPrintf("Running 'git pull' to download latest apps\n")
-	pullOutput, err := GitPull(appsFolder)
-	if err != nil {
-		errMsgTemplate := "Unable to Git pull latest apps - %v\n"
-		errMsgTemplate += "The 'kubemart init' command may solve this problem"
-		return false, fmt.Errorf(errMsgTemplate, err)
+	previousCommits := make(map[string]string)
+	for _, source := range sources {
+		catalogFolder, err := GetCatalogDirectoryPath(source.Name)
+		if err != nil {
+			return false, fmt.Errorf("unable to load kubemart paths - %v", err)
+		}
+
+		if _, err := os.Stat(catalogFolder); os.IsNotExist(err) {
+			DebugPrintf("Running 'git clone' to download %s catalog\n", source.Name)
+			err = CloneCatalog(source)
+			if err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		if source.Pin != "" {
+			DebugPrintf("%s catalog is pinned at %s. Git pull is not needed.\n", source.Name, source.Pin)
+			continue
+		}
+
+		previousCommit, err := GitLatestCommitHash(catalogFolder)
+		if err != nil {
+			errMsgTemplate := "Unable to get the commit of %s catalog - %v\n"
+			errMsgTemplate += "The 'kubemart catalog repair' command may solve this problem"
+			return false, fmt.Errorf(errMsgTemplate, source.Name, err)
+		}
+
+		DebugPrintf("Running 'git pull' to download latest apps of %s catalog\n", source.Name)
+		pullOutput, err := GitPull(catalogFolder, source.Ref)
+		if err != nil {
+			errMsgTemplate := "Unable to Git pull latest apps of %s catalog - %v\n"
+			errMsgTemplate += "The 'kubemart catalog repair' command may solve this problem"
+			return false, fmt.Errorf(errMsgTemplate, source.Name, err)
+		}
+		DebugPrintf("Pull output: %+v\n", pullOutput)
+		previousCommits[source.Name] = previousCommit
+	}
+
+	// remember where the catalogs were, for 'kubemart catalog changes'
+	if len(previousCommits) > 0 {
+		config, err := ReadConfigFile()
+		if err != nil {
+			return false, err
+		}
+
+		if config.PreviousCommits == nil {
+			config.PreviousCommits = make(map[string]string)
+		}
+		for name, commit := range previousCommits {
+			config.PreviousCommits[name] = commit
+		}
+
+		err = WriteConfigFile(config)
+		if err != nil {
+			return false, err
+		}
 	}
-	DebugPrintf("Pull output: %+v\n", pullOutput)
 
 	err = UpdateConfigFileLastUpdatedTimestamp()
 	if err != nil {
@@ -414,41 +432,30 @@ func UpdateAppsCacheIfStale() (bool, error) {
 
 // GitLatestCommitHash will return the last commit (short version) from Git folder
 func GitLatestCommitHash(directory string) (string, error) {
-	args := []string{
-		"-C",
-		path.Clean(directory),
-		"rev-parse",
-		"--short",
-		"HEAD",
-	}
-	DebugPrintf("git command args - %v\n", args)
+	return GetCatalogSyncer().LatestCommitHash(directory)
+}
 
-	out, err := exec.Command("git", args...).Output()
-	if err != nil {
-		return "", err
-	}
-	return string(out), nil
+// GitCheckout will check out the given commit in the context of given directory
+func GitCheckout(directory, commit string) error {
+	DebugPrintf("Checking out %s in %s\n", commit, directory)
+	return GetCatalogSyncer().Checkout(directory, commit)
 }
 
 // GetPostInstallMarkdown will fetch app's post_install.md and return it as string
 func GetPostInstallMarkdown(appName string) (string, error) {
-	bp, err := GetKubemartPaths()
+	appDirPath, err := GetAppDirectoryPath(appName)
 	if err != nil {
-		return "", fmt.Errorf("unable to get kubemart paths - %v", err.Error())
+		return "", err
 	}
 
-	appManifestPath := filepath.Join(bp.AppsDirectoryPath, appName, "post_install.md")
+	appManifestPath := filepath.Join(appDirPath, "post_install.md")
 	DebugPrintf("App post install file - %s\n", appManifestPath)
 	file, err := ioutil.ReadFile(appManifestPath)
 	if err != nil {
 		return "", fmt.Errorf("unable to load post-install notes for this app - %v", err.Error())
 	}
 
-	if runtime.GOOS == "windows" {
-		return string(file), nil
-	}
-
-	out, err := glamour.Render(string(file), "dark")
+	out, err := RenderMarkdown(string(file))
 	if err != nil {
 		return out, fmt.Errorf("unable to format the post-install - %v", err.Error())
 	}
@@ -456,14 +463,61 @@ func GetPostInstallMarkdown(appName string) (string, error) {
 	return out, nil
 }
 
+// GetReadmeMarkdown will fetch app's README.md and return it as string.
+// Not every app has one, in that case an empty string is returned.
+func GetReadmeMarkdown(appName string) (string, error) {
+	appDirPath, err := GetAppDirectoryPath(appName)
+	if err != nil {
+		return "", err
+	}
+
+	for _, fileName := range []string{"README.md", "readme.md"} {
+		file, err := ioutil.ReadFile(filepath.Join(appDirPath, fileName))
+		if err != nil {
+			continue
+		}
+
+		out, err := RenderMarkdown(string(file))
+		if err != nil {
+			return out, fmt.Errorf("unable to format the readme - %v", err.Error())
+		}
+		return out, nil
+	}
+
+	return "", nil
+}
+
+// RenderMarkdown formats markdown for the terminal. On Windows, it's returned as is.
+func RenderMarkdown(markdown string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return markdown, nil
+	}
+
+	return glamour.Render(markdown, "dark")
+}
+
+// ParseDependency takes a dependency as declared in app's manifest.yaml
+// i.e. APP_NAME[:PLAN] and returns the app name (lowercase) and the plan label
+func ParseDependency(dependency string) (string, string) {
+	splitted := strings.SplitN(dependency, ":", 2)
+	appName := strings.ToLower(strings.TrimSpace(splitted[0]))
+	if len(splitted) == 2 {
+		return appName, strings.TrimSpace(splitted[1])
+	}
+
+	return appName, ""
+}
+
+// GetAppManifest will parse app's manifest.yaml. The appName can be
+// in APP_NAME or SOURCE/APP_NAME format.
 func GetAppManifest(appName string) (AppManifest, error) {
 	manifest := AppManifest{}
-	bp, err := GetKubemartPaths()
+	appDirPath, err := GetAppDirectoryPath(appName)
 	if err != nil {
 		return manifest, err
 	}
 
-	appManifestPath := fmt.Sprintf("%s/%s/manifest.yaml", bp.AppsDirectoryPath, appName)
+	appManifestPath := filepath.Join(appDirPath, "manifest.yaml")
 	file, err := ioutil.ReadFile(appManifestPath)
 	if err != nil {
 		return manifest, err
@@ -477,44 +531,37 @@ func GetAppManifest(appName string) (AppManifest, error) {
 	return manifest, nil
 }
 
-// GetAppPlans returns app plan labels e.g. ["5GB", "10GB", "20GB"]
+// GetAppPlans returns app plan labels from the smallest to the largest e.g. ["5GB", "10GB", "20GB"]
 func GetAppPlans(appName string) ([]string, error) {
-	plans := []string{}
 	manifest, err := GetAppManifest(appName)
 	if err != nil {
-		return plans, err
+		return []string{}, err
 	}
 
-	for _, plan := range manifest.Plans {
-		label := plan.Label
-		plans = append(plans, label)
-	}
-
-	return plans, nil
+	return SortAppPlans(manifest), nil
 }
 
-// GetSmallestAppPlan take plan labels slice e.g. ["5GB", "10GB", "20GB"]
-// and return the first one e.g. 5GB (string)
+// GetSmallestAppPlan take plan labels slice e.g. ["20GB", "500MB", "1TB"]
+// and return the smallest one e.g. 500MB (string). When the labels are not
+// sizes, it returns the first one (see GetAppPlans for their order).
 func GetSmallestAppPlan(plans []string) string {
-	return plans[0]
+	return sortPlans(plans, ParsePlanSize)[0]
 }
 
+// GetAppPlanVariableName returns the configuration key whose value is sent as
+// the App's plan. When plans configure several keys, see planVariableName.
 func GetAppPlanVariableName(appName string) (string, error) {
 	manifest, err := GetAppManifest(appName)
 	if err != nil {
 		return "", err
 	}
 
-	planVariableNames := []string{}
-	for _, plan := range manifest.Plans {
-		conf := plan.Configuration
-		keys := reflect.ValueOf(conf).MapKeys()
-		for i := 0; i < len(keys); i++ {
-			planVariableNames = append(planVariableNames, keys[i].String())
-		}
+	planVariableName := planVariableName(manifest)
+	if planVariableName == "" {
+		return "", fmt.Errorf("%s app does not have any plan configuration", appName)
 	}
 
-	return planVariableNames[0], nil
+	return planVariableName, nil
 }
 
 // GetAppPlanValueByLabel will return the value of the plan. For example,
@@ -960,18 +1007,10 @@ func GetInstalledOperatorVersion() (string, error) {
 }
 
 // IsAppExist returns 'true' if the lookup app exists
-// in ~/.kubemart/apps folder. Otherwise, it returns 'false'.
+// in any of the catalogs. Otherwise, it returns 'false'.
 func IsAppExist(appName string) bool {
-	bp, err := GetKubemartPaths()
-	if err != nil {
-		return false
-	}
-
-	appFolderPath := filepath.Join(bp.AppsDirectoryPath, appName)
-	if _, err := os.Stat(appFolderPath); !os.IsNotExist(err) {
-		return true
-	}
-	return false
+	_, err := GetAppDirectoryPath(appName)
+	return err == nil
 }
 
 // GetLatestOperatorReleaseVersion will fetch the latest operator release
@@ -1052,53 +1091,46 @@ func IsServiceAccountExist() (bool, error) {
 	return true, nil
 }
 
-// CloneAppFilesIfNotExist will perform 'git clone' operation by cloning
-// apps from marketplace repository. If the '~/.kubemart' already exists,
-// it will not proceed with clone operation and return `nil` error.
+// CloneAppFilesIfNotExist will clone every catalog that is missing or broken
+// (e.g. left behind by an interrupted clone) into ~/.kubemart folder.
+// Catalogs that are already cloned are left untouched.
 func CloneAppFilesIfNotExist() error {
 	kubemartPaths, err := GetKubemartPaths()
 	if err != nil {
 		return fmt.Errorf("unable to load Kubemart paths - %v", err.Error())
 	}
 
-	kubemartDirPath := kubemartPaths.RootDirectoryPath
-	appsDirPath := kubemartPaths.AppsDirectoryPath
-	configFilePath := kubemartPaths.ConfigFilePath
-
-	// Do not clone if kubemartDir already exist
-	_, err = os.Stat(kubemartDirPath)
-	if !os.IsNotExist(err) {
-		DebugPrintf("%s already exist. Skipping clone operation.\n", kubemartDirPath)
-		return nil
+	err = os.MkdirAll(kubemartPaths.RootDirectoryPath, 0755)
+	if err != nil {
+		return fmt.Errorf("unable to create ~/.kubemart directory ($ mkdir -p ~/.kubemart)")
 	}
 
-	// Check if git is installed
-	gitProgram := "git"
-	if !IsCommandAvailable(gitProgram) {
-		return fmt.Errorf("%s program not found - please install it first and retry", gitProgram)
+	sources, err := GetCatalogSources()
+	if err != nil {
+		return fmt.Errorf("unable to load catalogs - %v\nThe 'kubemart catalog repair' command may solve this problem", err)
 	}
 
-	// When kubemartDir is not exist, create it (with apps folder and config.json file)
-	fmt.Println("Fetching apps...")
+	cloned := false
+	for _, source := range sources {
+		if IsCatalogCloned(source.Name) {
+			DebugPrintf("%s catalog already cloned. Skipping clone operation.\n", source.Name)
+			continue
+		}
 
-	// Create apps folder
-	err = os.MkdirAll(appsDirPath, 0755)
-	if err != nil {
-		return fmt.Errorf("unable to create ~/.kubemart/apps directory ($ mkdir -p ~/.kubemart/apps)")
-	}
+		if !cloned {
+			fmt.Println("Fetching apps...")
+		}
 
-	// Create config.json file
-	_, err = os.Create(configFilePath)
-	if err != nil {
-		return fmt.Errorf("unable to create ~/.kubemart/config.json file")
+		err = CloneCatalog(source)
+		if err != nil {
+			return fmt.Errorf("unable to clone marketplace - %v", err)
+		}
+		cloned = true
 	}
 
-	// Clone
-	cloneOutput, err := GitClone(appsDirPath)
-	if err != nil {
-		return fmt.Errorf("unable to clone marketplace - %v", err)
+	if !cloned {
+		return nil
 	}
-	DebugPrintf("Clone output: %s\n", cloneOutput)
 
 	// Update timestamp
 	err = UpdateConfigFileLastUpdatedTimestamp()
@@ -313,7 +313,8 @@ func TestAvailableProgram(t *testing.T) {
 func TestGitClone(t *testing.T) {
 	homeDir, _ := os.UserHomeDir()
 	targetDir := fmt.Sprintf("%s/.kubemart/apps", homeDir)
-	output, _ := GitClone(targetDir)
+	source := GetDefaultCatalogSource()
+	output, _ := GitClone(source.URL, source.Ref, targetDir)
 	fmt.Printf("Clone output: %s\n", output)
 }
 
@@ -343,7 +344,7 @@ func TestGitPull(t *testing.T) {
 	hashAfter, _ := GitLatestCommitHash(targetDir)
 	fmt.Printf("After reset commit: %s\n", hashAfter)
 
-	output, _ := GitPull(targetDir)
+	output, _ := GitPull(targetDir, GetDefaultCatalogSource().Ref)
 	fmt.Printf("Pull output: %s\n", output)
 
 	hashAfterPull, _ := GitLatestCommitHash(targetDir)
@@ -849,6 +850,20 @@ func TestGetAppPlanValueByLabel2(t *testing.T) {
 	}
 }
 
+func TestParseDependency1(t *testing.T) {
+	name, plan := ParseDependency("Longhorn")
+	if name != "longhorn" || plan != "" {
+		t.Errorf("Expected longhorn and empty plan but got %s and %s", name, plan)
+	}
+}
+
+func TestParseDependency2(t *testing.T) {
+	name, plan := ParseDependency("mariadb:5GB")
+	if name != "mariadb" || plan != "5GB" {
+		t.Errorf("Expected mariadb and 5GB but got %s and %s", name, plan)
+	}
+}
+
 // --------------------
 // Test helpers
 // --------------------
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// GetAppConfigurationKeys returns the configuration keys declared by the plans of the app, sorted
+func GetAppConfigurationKeys(appName string) ([]string, error) {
+	manifest, err := GetAppManifest(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	keys := []string{}
+	for _, plan := range manifest.Plans {
+		for key := range plan.Configuration {
+			if !found[key] {
+				found[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// GetAppPlanConfiguration returns every configuration value of the plan, keyed by
+// variable name e.g. {"VOLUME_SIZE": "5Gi", "REPLICAS": "1"}
+func GetAppPlanConfiguration(appName, planLabel string) (map[string]string, error) {
+	manifest, err := GetAppManifest(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plan := range manifest.Plans {
+		if plan.Label != planLabel {
+			continue
+		}
+
+		configuration := make(map[string]string)
+		for key, value := range plan.Configuration {
+			configuration[key] = value.Value
+		}
+		return configuration, nil
+	}
+
+	return nil, fmt.Errorf("%s app does not have %s plan", appName, planLabel)
+}
+
+// ParseValues reads the values file (if any) and applies the KEY=VALUE pairs on top of it
+func ParseValues(setValues []string, valuesFilePath string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	if valuesFilePath != "" {
+		file, err := ioutil.ReadFile(valuesFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s file - %v", valuesFilePath, err)
+		}
+
+		// numbers and booleans are accepted too e.g. "REPLICAS: 3"
+		fileValues := make(map[string]interface{})
+		err = yaml.Unmarshal(file, &fileValues)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s file - %v", valuesFilePath, err)
+		}
+
+		for key, value := range fileValues {
+			switch value.(type) {
+			case map[interface{}]interface{}, []interface{}:
+				return nil, fmt.Errorf("%s value in %s file must be a string, a number or a boolean", key, valuesFilePath)
+			}
+			values[key] = fmt.Sprint(value)
+		}
+	}
+
+	for _, setValue := range setValues {
+		splitted := strings.SplitN(setValue, "=", 2)
+		if len(splitted) != 2 || strings.TrimSpace(splitted[0]) == "" {
+			return nil, fmt.Errorf("%q must be in KEY=VALUE format", setValue)
+		}
+		values[strings.TrimSpace(splitted[0])] = splitted[1]
+	}
+
+	return values, nil
+}
+
+// ValidateAppValues returns an error if a key is not declared by the plans of the app,
+// or if it's not the plan key: the operator only reads the plan key (see
+// GetAppPlanVariableName), the other keys always get the value of the plan.
+func ValidateAppValues(appName string, values map[string]string) error {
+	keys, err := GetAppConfigurationKeys(appName)
+	if err != nil {
+		return err
+	}
+
+	unknownKeys := []string{}
+	for key := range values {
+		if !containsString(keys, key) {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	sort.Strings(unknownKeys)
+
+	if len(unknownKeys) > 0 && len(keys) == 0 {
+		return fmt.Errorf("%s app can't be configured - it does not declare any configuration key", appName)
+	}
+
+	if len(unknownKeys) > 0 {
+		return fmt.Errorf("%s app does not declare %s - supported keys are %s", appName, strings.Join(unknownKeys, ", "), strings.Join(keys, ", "))
+	}
+
+	planKey, err := GetAppPlanVariableName(appName)
+	if err != nil {
+		return err
+	}
+
+	planKeys := []string{}
+	for key := range values {
+		if key != planKey {
+			planKeys = append(planKeys, key)
+		}
+	}
+	sort.Strings(planKeys)
+
+	if len(planKeys) > 0 {
+		return fmt.Errorf("%s can't be set, the operator only supports overriding %s of %s app - please choose another plan instead", strings.Join(planKeys, ", "), planKey, appName)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubemart/kubemart-cli/test/fixtures"
+)
+
+const multiValuesManifest = `plans:
+- label: small
+  configuration:
+    VOLUME_SIZE:
+      value: 5Gi
+    REPLICAS:
+      value: "1"
+- label: large
+  configuration:
+    VOLUME_SIZE:
+      value: 20Gi
+    REPLICAS:
+      value: "3"
+`
+
+func TestGetAppPlanConfiguration(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{"minio/manifest.yaml": multiValuesManifest})
+	defer cleanup()
+
+	configuration, err := GetAppPlanConfiguration("minio", "large")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(configuration) != 2 || configuration["VOLUME_SIZE"] != "20Gi" || configuration["REPLICAS"] != "3" {
+		t.Errorf("Expected both values of large plan but got %v", configuration)
+	}
+
+	planKey, _ := GetAppPlanVariableName("minio")
+	if planKey != "VOLUME_SIZE" {
+		t.Errorf("Expected the size to be the plan variable rather than the replicas but got %s", planKey)
+	}
+
+	_, err = GetAppPlanConfiguration("minio", "medium")
+	if err == nil {
+		t.Error("Expected an error for an unknown plan")
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "values")
+	defer os.RemoveAll(dir)
+
+	valuesFilePath := filepath.Join(dir, "values.yaml")
+	_ = ioutil.WriteFile(valuesFilePath, []byte("REPLICAS: 3\nVOLUME_SIZE: 10Gi\n"), 0644)
+
+	values, err := ParseValues([]string{"VOLUME_SIZE=15Gi", "PASSWORD=a=b"}, valuesFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"REPLICAS": "3", "VOLUME_SIZE": "15Gi", "PASSWORD": "a=b"}
+	if len(values) != len(expected) {
+		t.Fatalf("Expected %v but got %v", expected, values)
+	}
+	for key, value := range expected {
+		if values[key] != value {
+			t.Errorf("Expected %s=%s but got %s", key, value, values[key])
+		}
+	}
+
+	_, err = ParseValues([]string{"VOLUME_SIZE"}, "")
+	if err == nil {
+		t.Error("Expected an error for a value without key")
+	}
+}
+
+func TestValidateAppValues(t *testing.T) {
+	cleanup := fixtures.UseOverlayCatalog(t, map[string]string{
+		"minio/manifest.yaml":   multiValuesManifest,
+		"linkerd/manifest.yaml": "version: 2.10\n",
+	})
+	defer cleanup()
+
+	err := ValidateAppValues("minio", map[string]string{"VOLUME_SIZE": "30Gi"})
+	if err != nil {
+		t.Errorf("Expected the plan key to be valid but got %v", err)
+	}
+
+	// the operator only reads the plan key
+	err = ValidateAppValues("minio", map[string]string{"REPLICAS": "2", "VOLUME_SIZE": "30Gi"})
+	if err == nil || !strings.Contains(err.Error(), "REPLICAS can't be set, the operator only supports overriding VOLUME_SIZE") {
+		t.Errorf("Expected an error for a key other than the plan key but got %v", err)
+	}
+
+	err = ValidateAppValues("minio", map[string]string{"REPLICA": "2"})
+	if err == nil || !strings.Contains(err.Error(), "supported keys are REPLICAS, VOLUME_SIZE") {
+		t.Errorf("Expected an error listing the supported keys but got %v", err)
+	}
+
+	err = ValidateAppValues("linkerd", map[string]string{"REPLICAS": "2"})
+	if err == nil {
+		t.Error("Expected an error for an app without configuration")
+	}
+}
@@ -0,0 +1,126 @@
+// Package fixtures holds the test setups shared by the unit tests of the
+// packages. It must not import them, so pkg/utils can use it too.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// UseTemporaryHome points $HOME to an empty folder, so ~/.kubemart
+// can be created and broken freely. Call the returned function to restore it.
+func UseTemporaryHome(t *testing.T) func() {
+	homeDir, err := ioutil.TempDir("", "kubemart-home")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	originalHomeDir := os.Getenv("HOME")
+	os.Setenv("HOME", homeDir)
+
+	return func() {
+		os.Setenv("HOME", originalHomeDir)
+		os.RemoveAll(homeDir)
+	}
+}
+
+// WriteFiles writes the files (keyed by relative path) into a new temporary
+// folder and returns it
+func WriteFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "kubemart-catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for fileName, content := range files {
+		filePath := filepath.Join(dir, fileName)
+		_ = os.MkdirAll(filepath.Dir(filePath), 0755)
+		err = ioutil.WriteFile(filePath, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// UseOverlayCatalog is like UseTemporaryHome, with the files written into an overlay
+// directory that is the only source of apps i.e. no catalog is registered
+func UseOverlayCatalog(t *testing.T, files map[string]string) func() {
+	restoreHome := UseTemporaryHome(t)
+	dir := WriteFiles(t, files)
+
+	// same format as ~/.kubemart/config.json written by utils.WriteConfigFile
+	config, _ := json.Marshal(map[string]interface{}{"catalogs": []string{}, "overlays": []string{dir}})
+	homeDir, _ := os.UserHomeDir()
+	_ = os.MkdirAll(filepath.Join(homeDir, ".kubemart"), 0755)
+	err := ioutil.WriteFile(filepath.Join(homeDir, ".kubemart", "config.json"), config, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		os.RemoveAll(dir)
+		restoreHome()
+	}
+}
+
+// NewLocalRepository creates a Git repository with one commit in a temporary
+// folder, so catalog syncing can be tested without network access
+func NewLocalRepository(t *testing.T) (string, string) {
+	dir, err := ioutil.TempDir("", "kubemart-catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := AddCommit(t, dir, "manifest.yaml", "version: 1.0.0\n")
+	return dir, hash
+}
+
+// AddCommit writes the file into the repository and commits it.
+// It returns the short hash of the new commit.
+func AddCommit(t *testing.T, dir, fileName, content string) string {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filePath := filepath.Join(dir, fileName)
+	_ = os.MkdirAll(filepath.Dir(filePath), 0755)
+	err = ioutil.WriteFile(filePath, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = worktree.Add(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := worktree.Commit(fmt.Sprintf("update %s", fileName), &git.CommitOptions{
+		Author: &object.Signature{Name: "kubemart", Email: "kubemart@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash.String()[:7]
+}